@@ -0,0 +1,331 @@
+package main
+
+// Copyright ⓒ 2013 Alexander Bauer and Luke Evers (see LICENSE.md)
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	apiPrefix = "/api/v1/"
+)
+
+type apiError struct {
+	Error  string // Description of the error
+	Status int    // HTTP status
+}
+
+type apiRepository struct {
+	Name        string // Name of the repository's directory
+	Path        string // Path of the repository below the top level
+	CloneURL    string // URL from which the repository can be cloned
+	Description string // Contents of .git/description, if set
+	Branch      string // Branch checked out in the repository
+	SHA         string // Full SHA of HEAD
+	LastCommit  string // Time of the most recent commit on HEAD
+}
+
+type apiTree struct {
+	Ref     string       // Ref at which the tree was listed
+	Path    string       // Path of the directory within the repository
+	Entries []*TreeEntry // Files and directories in the tree
+}
+
+type apiBlob struct {
+	Ref      string // Ref at which the file was retrieved
+	Path     string // Path of the file within the repository
+	Size     int    // Size of the file in bytes
+	Encoding string // Either "utf-8" or "base64"
+	Content  string // Contents of the file, in the given encoding
+}
+
+type apiCommit struct {
+	*Commit
+	Files []*fileDiff // Changes made to each file
+}
+
+type apiLog struct {
+	Ref     string    // Ref from which the history was walked
+	Path    string    // Path to which the history is limited, if any
+	Commits []*Commit // Commits in this page of history
+	Next    string    // SHA to pass as "after" for the next page, if any
+}
+
+type apiRefs struct {
+	Refs []*Ref // Branches or tags, with their tip commits
+}
+
+type apiCompare struct {
+	Base    string      // Ref being compared against
+	Head    string      // Ref being compared
+	Commits []*Commit   // Commits in head which are not in base
+	Files   []*fileDiff // Changes made to each file
+	Added   int         // Total lines added
+	Deleted int         // Total lines removed
+}
+
+// HandleAPI serves the JSON API, which lives below apiPrefix. The
+// repositories themselves are listed at "repos", and each repository
+// is found below it, as in the web interface. For example,
+//
+//	/api/v1/repos/<repository>/tree/<ref>/<path>
+//	/api/v1/repos/<repository>/blob/<ref>/<path>
+//	/api/v1/repos/<repository>/log/<ref>/<path>
+//	/api/v1/repos/<repository>/commit/<sha>
+//	/api/v1/repos/<repository>/compare/<base>...<head>
+//	/api/v1/repos/<repository>/branches
+//	/api/v1/repos/<repository>/tags
+func HandleAPI(w http.ResponseWriter, req *http.Request) {
+	l.Printf("API request for %q from %s\n", req.URL.Path, req.RemoteAddr)
+	rest := strings.TrimPrefix(req.URL.Path, apiPrefix)
+	switch {
+	case rest == "repos" || rest == "repos/":
+		apiRepositories(w, req)
+		return
+	case !strings.HasPrefix(rest, "repos/"):
+		writeAPIError(w, http.StatusNotFound)
+		return
+	}
+
+	// The remainder of the path is treated exactly as it would be by
+	// the web interface. It is rooted so that it cannot escape the
+	// top level directory.
+	r, p := findRoot(strings.TrimPrefix(rest, "repos/"))
+	if r == nil {
+		writeAPIError(w, http.StatusNotFound)
+		return
+	}
+	repository, file, view, status := SplitRepository(r.Dir, p)
+	if status == http.StatusOK {
+		status = readStatus(req, repository)
+	}
+	if status != http.StatusOK {
+		writeAPIError(w, status)
+		return
+	}
+	if ok, _ := isGit(repository); !ok {
+		writeAPIError(w, http.StatusNotFound)
+		return
+	}
+	g := &git{
+		Path: repository,
+		ctx:  req.Context(),
+	}
+
+	// Views of the contents of the repository may be qualified with a
+	// ref, or have one given by the "r" form value.
+	ref := "HEAD"
+	switch view {
+	case "tree", "blob", "raw", "log":
+		if pathRef, remainder, ok := splitRef(g, g.RefNames(), file); ok {
+			ref, file = pathRef, remainder
+		}
+	}
+	if r := req.FormValue("r"); len(r) != 0 {
+		if !g.RefExists(r) {
+			writeAPIError(w, http.StatusBadRequest)
+			return
+		}
+		ref = r
+	}
+	file = strings.Trim(file, "/")
+	if file == "." {
+		file = ""
+	}
+
+	var v interface{}
+	var err error
+	switch view {
+	case "":
+		v = makeAPIRepository(req, repository)
+	case "tree":
+		t := &apiTree{
+			Ref:  ref,
+			Path: file,
+		}
+		t.Entries, err = g.ListTree(ref, file)
+		v = t
+	case "blob", "raw":
+		var contents []byte
+		contents, err = g.GetFile(ref, file)
+		v = makeAPIBlob(ref, file, contents)
+	case "log":
+		v, err = makeAPILog(req, g, ref, file)
+	case "commit":
+		if !g.RefExists(file) {
+			writeAPIError(w, http.StatusNotFound)
+			return
+		}
+		v, err = makeAPICommit(g, file)
+	case "compare":
+		refs := strings.SplitN(file, "...", 2)
+		if len(refs) != 2 || !g.RefExists(refs[0]) || !g.RefExists(refs[1]) {
+			writeAPIError(w, http.StatusNotFound)
+			return
+		}
+		v, err = makeAPICompare(g, refs[0], refs[1])
+	case "branches":
+		r := &apiRefs{}
+		r.Refs, err = g.Refs("refs/heads")
+		v = r
+	case "tags":
+		r := &apiRefs{}
+		r.Refs, err = g.Refs("refs/tags")
+		v = r
+	default:
+		writeAPIError(w, http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		l.Printf("API request for %q produced error: %s\n", req.URL.Path, err)
+		writeAPIError(w, gitStatus(err))
+		return
+	}
+	writeJSON(w, http.StatusOK, v)
+}
+
+// apiRepositories lists every servable repository below the top level
+// directory, in the same way as the top level search.
+func apiRepositories(w http.ResponseWriter, req *http.Request) {
+	repositories := make([]*apiRepository, 0)
+	for _, r := range roots {
+		for _, repository := range FindRepositories(r.Dir) {
+			if CanRead(requestUser(req), repository) {
+				repositories = append(repositories,
+					makeAPIRepository(req, repository))
+			}
+		}
+	}
+	writeJSON(w, http.StatusOK, repositories)
+}
+
+// makeAPIRepository describes the repository at the given path.
+func makeAPIRepository(req *http.Request, repository string) *apiRepository {
+	g := &git{
+		Path: repository,
+		ctx:  req.Context(),
+	}
+	p := "/" + strings.TrimPrefix(servedPath(repository), "/")
+	return &apiRepository{
+		Name:        path.Base(repository),
+		Path:        p,
+		CloneURL:    requestScheme(req) + "://" + req.Host + strings.TrimRight(p, "/") + "/.git",
+		Description: gitDescription(repository),
+		Branch:      g.Branch("HEAD"),
+		SHA:         g.FullSHA("HEAD"),
+		LastCommit:  g.LastCommitTime("HEAD"),
+	}
+}
+
+// makeAPIBlob prepares the contents of a file for JSON. Text is given
+// as it is, but anything which is not valid UTF-8 is encoded in
+// base64.
+func makeAPIBlob(ref, file string, contents []byte) *apiBlob {
+	b := &apiBlob{
+		Ref:  ref,
+		Path: file,
+		Size: len(contents),
+	}
+	if utf8.Valid(contents) {
+		b.Encoding = "utf-8"
+		b.Content = string(contents)
+	} else {
+		b.Encoding = "base64"
+		b.Content = base64.StdEncoding.EncodeToString(contents)
+	}
+	return b
+}
+
+// makeAPILog retrieves a page of history, beginning after the commit
+// given by the "after" form value, if any. The size of the page is
+// given by the "c" form value, and the history may be filtered by the
+// "author", "since", and "until" form values, as in MakeLogPage.
+func makeAPILog(req *http.Request, g *git, ref, file string) (*apiLog, error) {
+	perPage, err := strconv.Atoi(req.FormValue("c"))
+	if err != nil || perPage <= 0 {
+		perPage = 30
+	}
+	commits, _, older, err := g.HistoryPage(ref, file,
+		req.FormValue("author"), req.FormValue("since"), req.FormValue("until"),
+		req.FormValue("after"), "", perPage)
+	if err != nil {
+		return nil, err
+	}
+
+	log := &apiLog{
+		Ref:     ref,
+		Path:    file,
+		Commits: commits,
+	}
+	if older && len(commits) != 0 {
+		log.Next = commits[len(commits)-1].SHA
+	}
+	return log, nil
+}
+
+// makeAPICommit retrieves a single commit, and the changes which it
+// made to each file.
+func makeAPICommit(g *git, ref string) (*apiCommit, error) {
+	diff, err := g.Diff(ref)
+	if err != nil {
+		return nil, err
+	}
+	c := &apiCommit{
+		Files: parseDiff(diff),
+	}
+	commits, err := g.Commits(ref, 1)
+	if len(commits) > 0 {
+		c.Commit = commits[0]
+	}
+	return c, err
+}
+
+// makeAPICompare retrieves the commits in head which are not in base,
+// and the changes which merging them would make.
+func makeAPICompare(g *git, base, head string) (*apiCompare, error) {
+	commits, err := g.Commits(base+".."+head, 0)
+	if err != nil {
+		return nil, err
+	}
+	diff, err := g.DiffRange(base, head)
+	if err != nil {
+		return nil, err
+	}
+	c := &apiCompare{
+		Base:    base,
+		Head:    head,
+		Commits: commits,
+		Files:   parseDiff(diff),
+	}
+	c.Added, c.Deleted = diffStat(c.Files)
+	return c, nil
+}
+
+// writeJSON marshals v and writes it with the given status.
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		l.Println("Could not marshal JSON:", err)
+		status = http.StatusInternalServerError
+		b = []byte("{\"Status\":500}")
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	w.Write(b)
+}
+
+// writeAPIError writes an error with the given status as JSON.
+func writeAPIError(w http.ResponseWriter, status int) {
+	setRetryAfter(w, status)
+	setChallenge(w, status)
+	writeJSON(w, status, &apiError{
+		Error:  http.StatusText(status),
+		Status: status,
+	})
+}
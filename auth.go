@@ -0,0 +1,264 @@
+package main
+
+// Copyright ⓒ 2013 Alexander Bauer and Luke Evers (see LICENSE.md)
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"golang.org/x/crypto/bcrypt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// authRealm is the realm given to clients when asking them to log in.
+const authRealm = "grove"
+
+// users holds the accounts which may log in, loaded from the files
+// given by -htpasswd and -tokens. If it is nil, authentication is
+// disabled, and every request is anonymous.
+var users *userDB
+
+type userDB struct {
+	passwords map[string][]byte // bcrypt hashes, by user
+	tokens    map[string]string // Users, by bearer token
+	lock      sync.Mutex        // Guards verified
+	verified  map[[32]byte]bool // Credentials already checked
+}
+
+// userKey is the context key under which the user who made a request
+// is stored.
+type userKey struct{}
+
+// LoadUsers reads the accounts from an htpasswd file, in which each
+// line is "<user>:<bcrypt hash>", and a file of bearer tokens, in which
+// each line is "<user> <token>". Either may be empty, and if both are,
+// it returns nil. Blank lines and those beginning with '#' are
+// ignored.
+func LoadUsers(htpasswd, tokens string) (db *userDB, err error) {
+	if len(htpasswd) == 0 && len(tokens) == 0 {
+		return nil, nil
+	}
+	db = &userDB{
+		passwords: make(map[string][]byte),
+		tokens:    make(map[string]string),
+		verified:  make(map[[32]byte]bool),
+	}
+	if len(htpasswd) != 0 {
+		err = readLines(htpasswd, func(line string) error {
+			parts := strings.SplitN(line, ":", 2)
+			if len(parts) != 2 || len(parts[0]) == 0 {
+				return errors.New("expected <user>:<hash>")
+			}
+			if _, err := bcrypt.Cost([]byte(parts[1])); err != nil {
+				return errors.New("the password of " + parts[0] +
+					" is not a bcrypt hash (use htpasswd -B)")
+			}
+			db.passwords[parts[0]] = []byte(parts[1])
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(tokens) != 0 {
+		err = readLines(tokens, func(line string) error {
+			fields := strings.Fields(line)
+			if len(fields) != 2 {
+				return errors.New("expected <user> <token>")
+			}
+			db.tokens[fields[1]] = fields[0]
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return db, nil
+}
+
+// Exists checks whether the given user may log in, with either a
+// password or a token.
+func (db *userDB) Exists(user string) bool {
+	if _, ok := db.passwords[user]; ok {
+		return true
+	}
+	for _, u := range db.tokens {
+		if u == user {
+			return true
+		}
+	}
+	return false
+}
+
+// readLines calls parse with each line of the file which is neither
+// blank nor a comment. Errors are prefixed with the file and line.
+func readLines(file string, parse func(line string) error) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if err := parse(line); err != nil {
+			return errors.New(file + ":" + strconv.Itoa(n) + ": " +
+				err.Error())
+		}
+	}
+	return scanner.Err()
+}
+
+// Authenticate checks the credentials given with a request, either as
+// a bearer token, or as a user and password with HTTP Basic
+// authentication. The password may also be one of the user's tokens,
+// so that git can log in with one. It returns the user, which is empty
+// if none was given, and whether the credentials were valid.
+func (db *userDB) Authenticate(req *http.Request) (user string, ok bool) {
+	if db == nil {
+		return "", true
+	}
+	header := req.Header.Get("Authorization")
+	if len(header) == 0 {
+		return "", true
+	}
+	if token, isBearer := cutPrefixFold(header, "Bearer "); isBearer {
+		user, ok = db.tokenUser(strings.TrimSpace(token))
+		return user, ok
+	}
+	user, password, isBasic := req.BasicAuth()
+	if !isBasic || len(user) == 0 {
+		return "", false
+	}
+	if tokenUser, ok := db.tokenUser(password); ok && tokenUser == user {
+		return user, true
+	}
+	return user, db.checkPassword(user, password)
+}
+
+// tokenUser retrieves the user to whom a token belongs. Every token is
+// compared, so that the time taken does not reveal which are close.
+func (db *userDB) tokenUser(token string) (user string, ok bool) {
+	for t, u := range db.tokens {
+		if subtle.ConstantTimeCompare([]byte(t), []byte(token)) == 1 {
+			user, ok = u, true
+		}
+	}
+	return
+}
+
+// checkPassword checks a user's password against its hash. As bcrypt
+// is deliberately slow, and clients send their credentials with every
+// request, passwords which have been checked once are remembered by
+// their SHA-256 sum.
+func (db *userDB) checkPassword(user, password string) bool {
+	hash, ok := db.passwords[user]
+	if !ok {
+		return false
+	}
+	sum := sha256.Sum256([]byte(user + "\x00" + password))
+	db.lock.Lock()
+	verified := db.verified[sum]
+	db.lock.Unlock()
+	if verified {
+		return true
+	}
+	if bcrypt.CompareHashAndPassword(hash, []byte(password)) != nil {
+		return false
+	}
+	db.lock.Lock()
+	db.verified[sum] = true
+	db.lock.Unlock()
+	return true
+}
+
+// cutPrefixFold removes a prefix from s, ignoring case, and reports
+// whether it was there.
+func cutPrefixFold(s, prefix string) (string, bool) {
+	if len(s) < len(prefix) || !strings.EqualFold(s[:len(prefix)], prefix) {
+		return s, false
+	}
+	return s[len(prefix):], true
+}
+
+// authHandler authenticates each request before passing it to fn,
+// which can retrieve the user with requestUser. Invalid credentials
+// are refused, as are anonymous requests if -auth-required is set.
+func authHandler(fn http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		db := setting(&users)
+		user, ok := db.Authenticate(req)
+		if !ok || (len(user) == 0 && db != nil && setting(fAuthRequired)) {
+			l.Printf("Authentication of %q from %s failed\n",
+				user, req.RemoteAddr)
+			setChallenge(w, http.StatusUnauthorized)
+			http.Error(w, http.StatusText(http.StatusUnauthorized),
+				http.StatusUnauthorized)
+			return
+		}
+		if len(user) != 0 {
+			req = req.WithContext(context.WithValue(req.Context(),
+				userKey{}, user))
+		}
+		fn(w, req)
+	}
+}
+
+// requestUser retrieves the user who made a request, or an empty
+// string if it was anonymous.
+func requestUser(req *http.Request) string {
+	user, _ := req.Context().Value(userKey{}).(string)
+	return user
+}
+
+// CanRead checks whether a user, which is empty if anonymous, has been
+// granted access to the repository. Repositories with no readers in
+// the configuration may be read by anyone, and the reader "*" stands
+// for any user who has logged in.
+func CanRead(user, repository string) bool {
+	readers := repoConfig(repository).Readers
+	if len(readers) == 0 {
+		return true
+	}
+	if len(user) == 0 {
+		return false
+	}
+	for _, reader := range readers {
+		if reader == user || reader == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// readStatus gives the status with which a request to read a
+// repository should be answered. Anonymous users who may not read it
+// are asked to log in, and other users are told it does not exist.
+func readStatus(req *http.Request, repository string) int {
+	user := requestUser(req)
+	switch {
+	case CanRead(user, repository):
+		return http.StatusOK
+	case len(user) == 0 && setting(&users) != nil:
+		return http.StatusUnauthorized
+	}
+	return http.StatusNotFound
+}
+
+// setChallenge asks the client to log in, if the status shows that it
+// must.
+func setChallenge(w http.ResponseWriter, status int) {
+	if status != http.StatusUnauthorized {
+		return
+	}
+	w.Header().Set("WWW-Authenticate", "Basic realm=\""+authRealm+"\"")
+}
@@ -0,0 +1,82 @@
+package main
+
+// Copyright ⓒ 2013 Alexander Bauer and Luke Evers (see LICENSE.md)
+
+import (
+	"golang.org/x/crypto/bcrypt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAuthenticate(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	htpasswd := filepath.Join(dir, "htpasswd")
+	tokens := filepath.Join(dir, "tokens")
+	if err = os.WriteFile(htpasswd,
+		[]byte("# users\nluke:"+string(hash)+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err = os.WriteFile(tokens,
+		[]byte("luke lukestoken\n\nalex alexstoken\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	db, err := LoadUsers(htpasswd, tokens)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		header string // Authorization
+		basic  []string
+		user   string
+		ok     bool
+	}{
+		{name: "anonymous", ok: true},
+		{name: "password", basic: []string{"luke", "secret"}, user: "luke", ok: true},
+		{name: "wrong password", basic: []string{"luke", "guess"}, user: "luke"},
+		{name: "unknown user", basic: []string{"nobody", "secret"}, user: "nobody"},
+		{name: "no user", basic: []string{"", "secret"}},
+		{name: "token as password", basic: []string{"alex", "alexstoken"}, user: "alex", ok: true},
+		{name: "token of another user", basic: []string{"alex", "lukestoken"}, user: "alex"},
+		{name: "bearer", header: "Bearer lukestoken", user: "luke", ok: true},
+		{name: "bearer case", header: "bearer  alexstoken ", user: "alex", ok: true},
+		{name: "bad bearer", header: "Bearer nothing"},
+		{name: "bearer prefix", header: "Bearer lukes"},
+		{name: "unknown scheme", header: "Digest username=luke"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "/", nil)
+			if len(test.header) != 0 {
+				req.Header.Set("Authorization", test.header)
+			}
+			if test.basic != nil {
+				req.SetBasicAuth(test.basic[0], test.basic[1])
+			}
+			// The second time, a password is checked from memory.
+			for i := 0; i < 2; i++ {
+				user, ok := db.Authenticate(req)
+				if user != test.user || ok != test.ok {
+					t.Errorf("got %q, %v, want %q, %v",
+						user, ok, test.user, test.ok)
+				}
+			}
+		})
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		var db *userDB
+		req, _ := http.NewRequest("GET", "/", nil)
+		req.SetBasicAuth("luke", "guess")
+		if user, ok := db.Authenticate(req); len(user) != 0 || !ok {
+			t.Errorf("got %q, %v, want anonymous", user, ok)
+		}
+	})
+}
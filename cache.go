@@ -0,0 +1,145 @@
+package main
+
+// Copyright ⓒ 2013 Alexander Bauer and Luke Evers (see LICENSE.md)
+
+import (
+	"container/list"
+	"strings"
+	"sync"
+)
+
+// queryCache holds the results of git queries, such as tree listings,
+// file contents, and logs, which are expensive to compute but never
+// change for a given commit. Entries are keyed by the repository, the
+// full SHA which the ref resolved to, and the path or other arguments,
+// so when a ref moves, it resolves to a new SHA, and the entries for
+// the old one are simply never used again. They are evicted, least
+// recently used first, once the cache exceeds its size.
+var queryCache *lruCache
+
+type lruCache struct {
+	lock  sync.Mutex
+	max   int                      // Maximum total size of the entries
+	size  int                      // Current total size of the entries
+	order *list.List               // Entries, most recently used first
+	items map[string]*list.Element // Entries by key
+}
+
+type cacheEntry struct {
+	key   string
+	value interface{}
+	size  int
+}
+
+// newCache creates a cache which holds entries up to the given total
+// size in bytes. If max is not positive, it returns nil, which is a
+// valid cache that holds nothing.
+func newCache(max int) *lruCache {
+	if max <= 0 {
+		return nil
+	}
+	return &lruCache{
+		max:   max,
+		order: list.New(),
+		items: make(map[string]*list.Element),
+	}
+}
+
+// Get retrieves the value for the given key, and marks it as recently
+// used.
+func (c *lruCache) Get(key string) (value interface{}, ok bool) {
+	if c == nil || len(key) == 0 {
+		return nil, false
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	e, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(e)
+	return e.Value.(*cacheEntry).value, true
+}
+
+// Add stores a value of approximately the given size in bytes, and
+// evicts the least recently used entries until the cache is within
+// its bounds. Values larger than the cache itself are not stored.
+func (c *lruCache) Add(key string, value interface{}, size int) {
+	if c == nil || len(key) == 0 || size > c.max {
+		return
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if e, ok := c.items[key]; ok {
+		c.remove(e)
+	}
+	c.items[key] = c.order.PushFront(&cacheEntry{key, value, size})
+	c.size += size
+	for c.size > c.max {
+		c.remove(c.order.Back())
+	}
+}
+
+// remove drops an entry from the cache. The lock must be held.
+func (c *lruCache) remove(e *list.Element) {
+	entry := e.Value.(*cacheEntry)
+	c.order.Remove(e)
+	delete(c.items, entry.key)
+	c.size -= entry.size
+}
+
+// cacheKey retrieves the key for a query of the given kind against
+// the commit to which ref currently resolves. If the ref cannot be
+// resolved, it returns an empty string, which is never cached.
+func (g *git) cacheKey(kind, ref string, args ...string) string {
+	if queryCache == nil {
+		return ""
+	}
+	if g.resolved == nil {
+		g.resolved = make(map[string]string)
+	}
+	sha, ok := g.resolved[ref]
+	if !ok {
+		sha = g.FullSHA(ref)
+		g.resolved[ref] = sha
+	}
+	if len(sha) == 0 {
+		return ""
+	}
+	return strings.Join(append([]string{g.Path, kind, sha}, args...), "\x00")
+}
+
+// sizeOfStrings estimates the memory used by a list of strings.
+func sizeOfStrings(s []string) (size int) {
+	for _, item := range s {
+		size += len(item) + 16
+	}
+	return
+}
+
+// sizeOfCommit estimates the memory used by a commit.
+func sizeOfCommit(c *Commit) int {
+	return 256 + len(c.Author) + len(c.AuthorEmail) + len(c.Committer) +
+		len(c.CommitterEmail) + len(c.Subject) + len(c.Body) +
+		sizeOfStrings(c.Parents)
+}
+
+// sizeOfCommits estimates the memory used by a list of commits.
+func sizeOfCommits(commits []*Commit) (size int) {
+	for _, c := range commits {
+		size += sizeOfCommit(c)
+	}
+	return
+}
+
+// freshCommits copies cached commits, updating their relative times,
+// which would otherwise be as old as the cache entry.
+func freshCommits(cached []*Commit) (commits []*Commit) {
+	commits = make([]*Commit, len(cached))
+	for i, c := range cached {
+		fresh := *c
+		fresh.Time = relativeTime(c.CommitDate)
+		commits[i] = &fresh
+	}
+	return
+}
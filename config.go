@@ -0,0 +1,574 @@
+package main
+
+// Copyright ⓒ 2013 Alexander Bauer and Luke Evers (see LICENSE.md)
+
+import (
+	"crypto/tls"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/BurntSushi/toml"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Config is the contents of a configuration file, which is written in
+// TOML. Every setting is optional, and any flag given on the command
+// line takes precedence over the file. Paths may begin with "~/" for
+// the home directory, and relative paths are taken from the directory
+// containing the file. For example:
+//
+//	bind = "127.0.0.1"
+//	port = 8860
+//	roots = ["~/dev", "/srv/git"]
+//	policy = "glob"
+//	allow = ["~/dev/*", "/srv/git/public/*"]
+//
+//	[cache]
+//	size = 128
+//
+//	[git]
+//	timeout = "30s"
+//	max_repo = 4
+//
+//	[auth]
+//	htpasswd = "~/.config/grove/htpasswd"
+//
+//	[tls]
+//	auto = true
+//
+//	[repos."~/dev/secret"]
+//	readers = ["alex", "luke"]
+//	push = true
+type Config struct {
+	Bind      string   // Interface to bind to
+	Port      int      // Port to listen on
+	Resources string   `toml:"res"` // Resources directory
+	Templates string   // Templates directory, if not <res>/templates
+	Roots     []string // Directories to serve
+	Policy    string   // Which repositories to serve
+	Perms     string   // Who must be able to read them, for "mode"
+	Allow     []string // Globs of paths to serve, for "glob"
+	Deny      []string // Globs of paths never to serve, for "glob"
+	Native    *bool    // Whether to read git objects directly
+
+	// ShutdownTimeout is the longest time to wait for requests to
+	// finish when stopping.
+	ShutdownTimeout string `toml:"shutdown_timeout"`
+
+	Cache struct {
+		Size *int // Megabytes of query results to cache
+	}
+	Git struct {
+		Timeout string // Maximum time for each git command
+		Max     *int   // Maximum git processes at once
+		MaxRepo *int   `toml:"max_repo"` // Maximum in each repository
+		Queue   string // Maximum time to wait for a process
+	}
+	Auth struct {
+		Htpasswd string // htpasswd file of users and bcrypt hashes
+		Tokens   string // File of users and bearer tokens
+		Required *bool  // Whether anonymous requests are refused
+	}
+	TLS struct {
+		Cert string // Certificate with which to serve HTTPS
+		Key  string // Private key of the certificate
+		Auto *bool  // Whether to generate a certificate if there is none
+	} `toml:"tls"`
+
+	// Repos holds settings for individual repositories, by path.
+	Repos map[string]*RepoConfig
+
+	file      string   // Path of the file which was read
+	undecoded []string // Settings in the file which are not known
+}
+
+// RepoConfig holds the settings which may be given for a single
+// repository, overriding those for the rest.
+type RepoConfig struct {
+	Hidden  bool     // Never serve or list the repository
+	MaxGit  *int     `toml:"max_git"` // Maximum git processes at once
+	Readers []string // Users who may read it, or "*" for any
+
+	Push     bool     // Whether users who have logged in may push
+	PushRefs []string `toml:"push_refs"` // Refs to which they may push
+	Pushers  []string // Users who may push, if not every reader
+}
+
+// repoConfigs are the settings of individual repositories, by their
+// cleaned absolute paths.
+var repoConfigs = make(map[string]*RepoConfig)
+
+// settingsLock guards the settings which are changed when the
+// configuration is reloaded, while requests are being served. These
+// are the flags named in configFlags, repoConfigs, policy, and users.
+var settingsLock sync.RWMutex
+
+// setting retrieves the current value of a setting which may be
+// reloaded, such as a flag.
+func setting[T any](p *T) T {
+	settingsLock.RLock()
+	defer settingsLock.RUnlock()
+	return *p
+}
+
+// configFlags are the flags which may be given in the configuration
+// file.
+var configFlags = []string{
+	"bind", "port", "res", "templates",
+	"policy", "perms", "allow", "deny", "native",
+	"cache-size", "git-timeout", "max-git", "max-git-repo", "git-queue",
+	"htpasswd", "tokens", "auth-required",
+	"tls-cert", "tls-key", "tls-auto", "shutdown-timeout",
+}
+
+// restartFlags are the settings which take effect only when grove is
+// started, and so are left as they are when the configuration is
+// reloaded. The directories which are served also stay the same.
+var restartFlags = map[string]bool{
+	"bind": true, "port": true,
+	"tls-cert": true, "tls-key": true, "tls-auto": true,
+	"cache-size": true, "max-git": true, "max-git-repo": true,
+	"git-queue": true,
+}
+
+// commandLine records which flags were given on the command line,
+// which always take precedence over the file.
+var commandLine map[string]bool
+
+// repoConfig retrieves the settings of the repository at the given
+// path. If there are none, it returns the defaults.
+func repoConfig(repository string) *RepoConfig {
+	settingsLock.RLock()
+	defer settingsLock.RUnlock()
+	if c, ok := repoConfigs[filepath.Clean(repository)]; ok {
+		return c
+	}
+	return &RepoConfig{}
+}
+
+// defaultConfigPath retrieves the location of the configuration file
+// which is read if none is given, usually ~/.config/grove/config.
+func defaultConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "grove", "config")
+}
+
+// LoadConfig reads the configuration file at the given path. If the
+// path is empty, the default file is read instead, and if it does not
+// exist, the configuration is empty.
+func LoadConfig(file string) (c *Config, err error) {
+	c = &Config{}
+	if len(file) == 0 {
+		file = defaultConfigPath()
+		if _, err = os.Stat(file); len(file) == 0 || os.IsNotExist(err) {
+			return c, nil
+		}
+	}
+	md, err := toml.DecodeFile(file, c)
+	if err != nil {
+		return nil, err
+	}
+	c.file = file
+	for _, key := range md.Undecoded() {
+		c.undecoded = append(c.undecoded, key.String())
+	}
+
+	// Paths are resolved once, here, so that they mean the same thing
+	// wherever they are used.
+	dir := filepath.Dir(file)
+	if socket, ok := strings.CutPrefix(c.Bind, unixPrefix); ok {
+		c.Bind = unixPrefix + expandPath(dir, socket)
+	}
+	c.Resources = expandPath(dir, c.Resources)
+	c.Templates = expandPath(dir, c.Templates)
+	c.Auth.Htpasswd = expandPath(dir, c.Auth.Htpasswd)
+	c.Auth.Tokens = expandPath(dir, c.Auth.Tokens)
+	c.TLS.Cert = expandPath(dir, c.TLS.Cert)
+	c.TLS.Key = expandPath(dir, c.TLS.Key)
+	for _, paths := range [][]string{c.Roots, c.Allow, c.Deny} {
+		for i, p := range paths {
+			paths[i] = expandPath(dir, p)
+		}
+	}
+	repos := make(map[string]*RepoConfig, len(c.Repos))
+	for p, repo := range c.Repos {
+		if repo != nil {
+			repos[expandPath(dir, p)] = repo
+		}
+	}
+	c.Repos = repos
+	return c, nil
+}
+
+// expandPath makes a path from the configuration file absolute,
+// replacing a leading "~" with the home directory, and taking relative
+// paths from dir. Empty paths are left empty.
+func expandPath(dir, p string) string {
+	if len(p) == 0 {
+		return p
+	}
+	if p == "~" || strings.HasPrefix(p, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			p = filepath.Join(home, p[1:])
+		}
+	}
+	if !filepath.IsAbs(p) {
+		p = filepath.Join(dir, p)
+	}
+	return filepath.Clean(p)
+}
+
+// flagValues retrieves the settings in the file which correspond to
+// flags, as the values which would be given on the command line.
+func (c *Config) flagValues() map[string]string {
+	values := make(map[string]string)
+	set := func(name, value string) {
+		if len(value) != 0 {
+			values[name] = value
+		}
+	}
+	setInt := func(name string, value *int) {
+		if value != nil {
+			values[name] = strconv.Itoa(*value)
+		}
+	}
+
+	set("bind", c.Bind)
+	if c.Port != 0 {
+		set("port", strconv.Itoa(c.Port))
+	}
+	set("res", c.Resources)
+	set("templates", c.Templates)
+	set("policy", c.Policy)
+	set("perms", c.Perms)
+	set("allow", strings.Join(c.Allow, ","))
+	set("deny", strings.Join(c.Deny, ","))
+	if c.Native != nil {
+		set("native", strconv.FormatBool(*c.Native))
+	}
+	setInt("cache-size", c.Cache.Size)
+	set("git-timeout", c.Git.Timeout)
+	setInt("max-git", c.Git.Max)
+	setInt("max-git-repo", c.Git.MaxRepo)
+	set("git-queue", c.Git.Queue)
+	set("htpasswd", c.Auth.Htpasswd)
+	set("tokens", c.Auth.Tokens)
+	if c.Auth.Required != nil {
+		set("auth-required", strconv.FormatBool(*c.Auth.Required))
+	}
+	set("tls-cert", c.TLS.Cert)
+	set("tls-key", c.TLS.Key)
+	if c.TLS.Auto != nil {
+		set("tls-auto", strconv.FormatBool(*c.TLS.Auto))
+	}
+	set("shutdown-timeout", c.ShutdownTimeout)
+	return values
+}
+
+// Apply sets every flag which was not given on the command line to its
+// value in the file, or if it has none, to its default, and records
+// the settings of individual repositories. If reloading, the flags in
+// restartFlags are not changed, and a change to one is only logged.
+// The settings lock must be held.
+func (c *Config) Apply(reloading bool) error {
+	if commandLine == nil {
+		commandLine = make(map[string]bool)
+		flag.Visit(func(f *flag.Flag) {
+			commandLine[f.Name] = true
+		})
+	}
+	values := c.flagValues()
+	for _, name := range configFlags {
+		if commandLine[name] {
+			continue
+		}
+		value, ok := values[name]
+		if !ok {
+			value = flag.Lookup(name).DefValue
+		}
+		if reloading && restartFlags[name] {
+			if flagChanged(flag.Lookup(name), value) {
+				l.Printf("Changing %s to %q requires a restart\n",
+					name, value)
+			}
+			continue
+		}
+		if err := flag.Set(name, value); err != nil {
+			return fmt.Errorf("%s: invalid value %q for %s: %s",
+				c.file, value, name, err)
+		}
+	}
+	repoConfigs = c.Repos
+	return nil
+}
+
+// flagChanged reports whether value differs from the current value of
+// the flag. Durations are compared by their length, as each may be
+// written in several ways.
+func flagChanged(f *flag.Flag, value string) bool {
+	if current, ok := f.Value.(flag.Getter).Get().(time.Duration); ok {
+		d, err := time.ParseDuration(value)
+		return err != nil || d != current
+	}
+	return value != f.Value.String()
+}
+
+// Configure applies the configuration, and sets up the policy and
+// users which follow from it and the flags, as Apply does. If anything
+// is invalid, the previous settings are left in place.
+func Configure(c *Config, reloading bool) (err error) {
+	settingsLock.Lock()
+	defer settingsLock.Unlock()
+
+	previous := make(map[string]string)
+	for _, name := range configFlags {
+		previous[name] = flag.Lookup(name).Value.String()
+	}
+	previousRepos := repoConfigs
+	defer func() {
+		if err != nil {
+			for name, value := range previous {
+				flag.Set(name, value)
+			}
+			repoConfigs = previousRepos
+		}
+	}()
+
+	if err = c.Apply(reloading); err != nil {
+		return err
+	}
+	p, err := newPolicy(*fPolicy, *fPerms,
+		splitList(*fAllow), splitList(*fDeny))
+	if err != nil {
+		return fmt.Errorf("-policy: %s", err)
+	}
+	db, err := LoadUsers(*fHtpasswd, *fTokens)
+	if err != nil {
+		return fmt.Errorf("reading users: %s", err)
+	}
+	policy, users = p, db
+	return nil
+}
+
+// Check validates the configuration, returning every problem found.
+// Settings are checked as they are written in the file, without the
+// flags which may override them.
+func (c *Config) Check() (problems []error) {
+	problem := func(format string, v ...interface{}) {
+		problems = append(problems, fmt.Errorf(format, v...))
+	}
+
+	for _, key := range c.undecoded {
+		problem("unknown setting %q", key)
+	}
+
+	if c.Port < 0 || c.Port > 65535 {
+		problem("port %d is out of range", c.Port)
+	}
+	if socket, ok := strings.CutPrefix(c.Bind, unixPrefix); ok {
+		if err := checkDir(filepath.Dir(socket)); err != nil {
+			problem("bind: %s", err)
+		}
+	}
+	policyName, perms := c.Policy, c.Perms
+	if len(policyName) == 0 {
+		policyName = "mode"
+	}
+	if len(perms) == 0 {
+		perms = "world"
+	}
+	if _, err := newPolicy(policyName, perms, c.Allow, c.Deny); err != nil {
+		problem("policy: %s", err)
+	}
+	if policyName != "glob" && len(c.Allow)+len(c.Deny) != 0 {
+		problem("allow and deny are only used by the glob policy")
+	}
+	if policyName != "mode" && len(c.Perms) != 0 {
+		problem("perms is only used by the mode policy")
+	}
+	for _, d := range []struct{ name, value string }{
+		{"git.timeout", c.Git.Timeout},
+		{"git.queue", c.Git.Queue},
+		{"shutdown_timeout", c.ShutdownTimeout},
+	} {
+		if len(d.value) == 0 {
+			continue
+		}
+		if t, err := time.ParseDuration(d.value); err != nil || t < 0 {
+			problem("%s: %q is not a valid duration", d.name, d.value)
+		}
+	}
+	for _, n := range []struct {
+		name  string
+		value *int
+	}{
+		{"cache.size", c.Cache.Size},
+		{"git.max", c.Git.Max},
+		{"git.max_repo", c.Git.MaxRepo},
+	} {
+		if n.value != nil && *n.value < 0 {
+			problem("%s must not be negative", n.name)
+		}
+	}
+
+	for _, dir := range []struct{ name, path string }{
+		{"res", c.Resources},
+		{"templates", c.Templates},
+	} {
+		if len(dir.path) != 0 {
+			if err := checkDir(dir.path); err != nil {
+				problem("%s: %s", dir.name, err)
+			}
+		}
+	}
+
+	// Several roots are served under their base names, so those must
+	// be distinct.
+	names := make(map[string]string)
+	for _, root := range c.Roots {
+		if err := checkDir(root); err != nil {
+			problem("roots: %s", err)
+		}
+		name := filepath.Base(root)
+		if other, ok := names[name]; ok && len(c.Roots) > 1 {
+			problem("roots: %s and %s would both be served as /%s",
+				other, root, name)
+		}
+		names[name] = root
+	}
+
+	// Users are checked when they are loaded, so any problem in the
+	// files is found here.
+	db, err := LoadUsers(c.Auth.Htpasswd, c.Auth.Tokens)
+	if err != nil {
+		problem("auth: %s", err)
+	}
+	if c.Auth.Required != nil && *c.Auth.Required &&
+		len(c.Auth.Htpasswd)+len(c.Auth.Tokens) == 0 {
+		problem("auth: required is set, but no users are given")
+	}
+
+	// A certificate which is generated need not exist yet, but one
+	// which is given must be usable.
+	auto := c.TLS.Auto != nil && *c.TLS.Auto
+	switch {
+	case !auto && (len(c.TLS.Cert) == 0) != (len(c.TLS.Key) == 0):
+		problem("tls: cert and key must be given together")
+	case !auto && len(c.TLS.Cert) != 0:
+		if _, err := tls.LoadX509KeyPair(c.TLS.Cert, c.TLS.Key); err != nil {
+			problem("tls: %s", err)
+		}
+	}
+
+	paths := make([]string, 0, len(c.Repos))
+	for p := range c.Repos {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	for _, p := range paths {
+		if ok, _ := isGit(p); !ok {
+			problem("repos: %s is not a git repository", p)
+		} else if len(c.Roots) != 0 && !underRoots(p, c.Roots) {
+			problem("repos: %s is not below any of the roots", p)
+		}
+		if max := c.Repos[p].MaxGit; max != nil && *max < 0 {
+			problem("repos: max_git for %s must not be negative", p)
+		}
+		repo := c.Repos[p]
+		if db == nil && err == nil &&
+			(len(repo.Readers) != 0 || repo.Push) {
+			problem("repos: %s is restricted to users, but none are given", p)
+		}
+		for _, list := range []struct {
+			name  string
+			users []string
+		}{
+			{"reader", repo.Readers},
+			{"pusher", repo.Pushers},
+		} {
+			for _, user := range list.users {
+				if db != nil && user != "*" && !db.Exists(user) {
+					problem("repos: %s %q of %s is not a user",
+						list.name, user, p)
+				}
+			}
+		}
+		if !repo.Push && len(repo.PushRefs)+len(repo.Pushers) != 0 {
+			problem("repos: push_refs and pushers are given for %s, "+
+				"but push is not set", p)
+		}
+		for _, ref := range repo.PushRefs {
+			if !strings.HasPrefix(ref, "refs/") {
+				problem("repos: push_refs of %s: %q does not begin "+
+					"with refs/", p, ref)
+			}
+		}
+	}
+	return
+}
+
+// checkDir checks that the given path is an existing directory.
+func checkDir(p string) error {
+	info, err := os.Stat(p)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return errors.New(p + " is not a directory")
+	}
+	return nil
+}
+
+// splitList splits a comma-separated list, as given to -allow and
+// -deny, leaving out empty elements.
+func splitList(list string) (elements []string) {
+	for _, e := range strings.Split(list, ",") {
+		if e = strings.TrimSpace(e); len(e) != 0 {
+			elements = append(elements, e)
+		}
+	}
+	return
+}
+
+// underRoots checks whether the path is one of the given directories,
+// or below one of them.
+func underRoots(p string, dirs []string) bool {
+	for _, dir := range dirs {
+		if _, ok := withinDir(p, dir); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckConfig validates the configuration file at the given path, or
+// the default file, and prints every problem found. It returns the
+// status with which grove should exit.
+func CheckConfig(file string) int {
+	if len(file) == 0 {
+		file = defaultConfigPath()
+	}
+	c, err := LoadConfig(file)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	problems := c.Check()
+	for _, err := range problems {
+		fmt.Fprintf(os.Stderr, "%s: %s\n", file, err)
+	}
+	if len(problems) != 0 {
+		return 1
+	}
+	fmt.Fprintln(os.Stdout, file+": OK")
+	return 0
+}
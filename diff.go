@@ -0,0 +1,176 @@
+package main
+
+// Copyright ⓒ 2013 Alexander Bauer and Luke Evers (see LICENSE.md)
+
+import (
+	"strconv"
+	"strings"
+)
+
+type fileDiff struct {
+	OldPath string      // Path of the file before the change
+	NewPath string      // Path of the file after the change
+	Added   int         // Number of added lines
+	Deleted int         // Number of removed lines
+	Lines   []*diffLine // Header, hunk, and content lines
+}
+
+type diffLine struct {
+	Class  string // One of "meta", "hunk", "add", "del", or "ctx"
+	OldNum string // Line number in the old file, if any
+	NewNum string // Line number in the new file, if any
+	Text   string // Text of the line, including its +/- prefix
+}
+
+// Name returns the path which best describes the file, noting
+// renames as "old → new".
+func (d *fileDiff) Name() string {
+	switch {
+	case d.NewPath == "":
+		return d.OldPath
+	case d.OldPath == "", d.OldPath == d.NewPath:
+		return d.NewPath
+	}
+	return d.OldPath + " → " + d.NewPath
+}
+
+// parseDiff splits the output of 'git show' or 'git diff' into a
+// fileDiff for each file touched, and classifies each line so that
+// additions and removals can be highlighted. Line numbers are tracked
+// from the hunk headers, except in combined diffs, where they are
+// left blank.
+func parseDiff(diff string) (files []*fileDiff) {
+	var f *fileDiff
+	var oldLine, newLine int
+	var inHunk, combined bool
+
+	for _, l := range strings.Split(strings.TrimRight(diff, "\n"), "\n") {
+		switch {
+		case strings.HasPrefix(l, "diff --git "),
+			strings.HasPrefix(l, "diff --cc "),
+			strings.HasPrefix(l, "diff --combined "):
+			f = &fileDiff{}
+			files = append(files, f)
+			inHunk = false
+			combined = !strings.HasPrefix(l, "diff --git ")
+			if !combined {
+				// Make a first guess at the paths from the header.
+				// These are corrected by the ---/+++ lines, if they
+				// are present.
+				names := strings.SplitN(strings.TrimPrefix(l,
+					"diff --git a/"), " b/", 2)
+				f.OldPath = names[0]
+				if len(names) == 2 {
+					f.NewPath = names[1]
+				}
+			} else {
+				f.OldPath = strings.SplitN(l, " ", 3)[2]
+				f.NewPath = f.OldPath
+			}
+			continue
+		case f == nil:
+			// Anything before the first header is ignored.
+			continue
+		case strings.HasPrefix(l, "@@"):
+			inHunk = true
+			oldLine, newLine = parseHunkHeader(l)
+			f.Lines = append(f.Lines, &diffLine{Class: "hunk", Text: l})
+			continue
+		case !inHunk:
+			switch {
+			case strings.HasPrefix(l, "--- "):
+				f.OldPath = diffPath(l[4:], "a/")
+			case strings.HasPrefix(l, "+++ "):
+				f.NewPath = diffPath(l[4:], "b/")
+			case strings.HasPrefix(l, "index "):
+			default:
+				f.Lines = append(f.Lines, &diffLine{Class: "meta", Text: l})
+			}
+			continue
+		}
+
+		line := &diffLine{Text: l}
+		// The prefix is one column wide for ordinary diffs, and one
+		// column per parent (two, for the usual merge) for combined
+		// diffs.
+		prefix := l
+		width := 1
+		if combined {
+			width = 2
+		}
+		if len(prefix) > width {
+			prefix = prefix[:width]
+		}
+		switch {
+		case strings.HasPrefix(l, "\\"):
+			line.Class = "meta"
+		case strings.Contains(prefix, "+"):
+			line.Class = "add"
+			f.Added++
+			if !combined {
+				line.NewNum = strconv.Itoa(newLine)
+				newLine++
+			}
+		case strings.Contains(prefix, "-"):
+			line.Class = "del"
+			f.Deleted++
+			if !combined {
+				line.OldNum = strconv.Itoa(oldLine)
+				oldLine++
+			}
+		default:
+			line.Class = "ctx"
+			if !combined {
+				line.OldNum = strconv.Itoa(oldLine)
+				line.NewNum = strconv.Itoa(newLine)
+				oldLine++
+				newLine++
+			}
+		}
+		f.Lines = append(f.Lines, line)
+	}
+	return
+}
+
+// diffStat totals the lines added and removed across several files.
+func diffStat(files []*fileDiff) (added, deleted int) {
+	for _, f := range files {
+		added += f.Added
+		deleted += f.Deleted
+	}
+	return
+}
+
+// parseHunkHeader retrieves the starting line numbers from a hunk
+// header of the form "@@ -a,b +c,d @@".
+func parseHunkHeader(l string) (oldStart, newStart int) {
+	fields := strings.Fields(l)
+	for i, field := range fields {
+		if i == 0 {
+			continue
+		}
+		if strings.HasPrefix(field, "@@") || len(field) < 2 {
+			// Stop at the closing @@, so that the function context
+			// which follows it isn't misread.
+			break
+		}
+		n, _ := strconv.Atoi(strings.SplitN(field[1:], ",", 2)[0])
+		switch field[0] {
+		case '-':
+			oldStart = n
+		case '+':
+			newStart = n
+		}
+	}
+	return
+}
+
+// diffPath strips the given prefix from a path in a ---/+++ line,
+// and returns an empty string for /dev/null.
+func diffPath(p, prefix string) string {
+	p = strings.TrimRight(p, "\t")
+	if p == "/dev/null" {
+		return ""
+	}
+	return strings.TrimPrefix(p, prefix)
+}
@@ -0,0 +1,167 @@
+package main
+
+// Copyright ⓒ 2013 Alexander Bauer and Luke Evers (see LICENSE.md)
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseHunkHeader(t *testing.T) {
+	tests := []struct {
+		header             string
+		oldStart, newStart int
+	}{
+		{"@@ -1,5 +1,6 @@", 1, 1},
+		{"@@ -0,0 +1 @@", 0, 1},
+		{"@@ -10 +12,3 @@", 10, 12},
+		{"@@ -10,2 +12,3 @@ func f(a, b int) -3 +4", 10, 12},
+		{"@@@ -4,2 -5,2 +6,3 @@@", 5, 6},
+		{"@@", 0, 0},
+	}
+	for _, test := range tests {
+		oldStart, newStart := parseHunkHeader(test.header)
+		if oldStart != test.oldStart || newStart != test.newStart {
+			t.Errorf("parseHunkHeader(%q) = %d, %d, want %d, %d",
+				test.header, oldStart, newStart, test.oldStart, test.newStart)
+		}
+	}
+}
+
+func TestParseDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		diff string
+		want []*fileDiff
+	}{{
+		name: "empty",
+		diff: "",
+	}, {
+		name: "change",
+		diff: "commit 1111111111111111111111111111111111111111\n" +
+			"Author: Luke <luke@example.com>\n" +
+			"\n" +
+			"    Change b to c\n" +
+			"\n" +
+			"diff --git a/f b/f\n" +
+			"index 1111111..2222222 100644\n" +
+			"--- a/f\n" +
+			"+++ b/f\n" +
+			"@@ -1,3 +1,3 @@\n" +
+			" a\n" +
+			"-b\n" +
+			"+c\n" +
+			" d\n",
+		want: []*fileDiff{{
+			OldPath: "f",
+			NewPath: "f",
+			Added:   1,
+			Deleted: 1,
+			Lines: []*diffLine{
+				{Class: "hunk", Text: "@@ -1,3 +1,3 @@"},
+				{Class: "ctx", OldNum: "1", NewNum: "1", Text: " a"},
+				{Class: "del", OldNum: "2", Text: "-b"},
+				{Class: "add", NewNum: "2", Text: "+c"},
+				{Class: "ctx", OldNum: "3", NewNum: "3", Text: " d"},
+			},
+		}},
+	}, {
+		name: "new and removed files",
+		diff: "diff --git a/new b/new\n" +
+			"new file mode 100644\n" +
+			"index 0000000..1111111\n" +
+			"--- /dev/null\n" +
+			"+++ b/new\n" +
+			"@@ -0,0 +1,2 @@\n" +
+			"+x\n" +
+			"+y\n" +
+			"\\ No newline at end of file\n" +
+			"diff --git a/old b/old\n" +
+			"deleted file mode 100644\n" +
+			"index 1111111..0000000\n" +
+			"--- a/old\n" +
+			"+++ /dev/null\n" +
+			"@@ -7 +0,0 @@\n" +
+			"-z\n",
+		want: []*fileDiff{{
+			NewPath: "new",
+			Added:   2,
+			Lines: []*diffLine{
+				{Class: "meta", Text: "new file mode 100644"},
+				{Class: "hunk", Text: "@@ -0,0 +1,2 @@"},
+				{Class: "add", NewNum: "1", Text: "+x"},
+				{Class: "add", NewNum: "2", Text: "+y"},
+				{Class: "meta", Text: "\\ No newline at end of file"},
+			},
+		}, {
+			OldPath: "old",
+			Deleted: 1,
+			Lines: []*diffLine{
+				{Class: "meta", Text: "deleted file mode 100644"},
+				{Class: "hunk", Text: "@@ -7 +0,0 @@"},
+				{Class: "del", OldNum: "7", Text: "-z"},
+			},
+		}},
+	}, {
+		name: "rename",
+		diff: "diff --git a/old name b/new name\n" +
+			"similarity index 100%\n" +
+			"rename from old name\n" +
+			"rename to new name\n",
+		want: []*fileDiff{{
+			OldPath: "old name",
+			NewPath: "new name",
+			Lines: []*diffLine{
+				{Class: "meta", Text: "similarity index 100%"},
+				{Class: "meta", Text: "rename from old name"},
+				{Class: "meta", Text: "rename to new name"},
+			},
+		}},
+	}, {
+		name: "combined",
+		diff: "diff --cc f\n" +
+			"index 1111111,2222222..3333333\n" +
+			"--- a/f\n" +
+			"+++ b/f\n" +
+			"@@@ -1,2 -1,2 +1,3 @@@\n" +
+			"  a\n" +
+			" -b\n" +
+			"+ c\n" +
+			"++d\n",
+		want: []*fileDiff{{
+			OldPath: "f",
+			NewPath: "f",
+			Added:   2,
+			Deleted: 1,
+			Lines: []*diffLine{
+				{Class: "hunk", Text: "@@@ -1,2 -1,2 +1,3 @@@"},
+				{Class: "ctx", Text: "  a"},
+				{Class: "del", Text: " -b"},
+				{Class: "add", Text: "+ c"},
+				{Class: "add", Text: "++d"},
+			},
+		}},
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := parseDiff(test.diff)
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %s, want %s", describeDiff(got),
+					describeDiff(test.want))
+			}
+		})
+	}
+}
+
+// describeDiff formats parsed files for test failures, as the
+// pointers within them would otherwise be printed.
+func describeDiff(files []*fileDiff) (s string) {
+	for _, f := range files {
+		s += "\n" + f.Name() + ":"
+		for _, l := range f.Lines {
+			s += "\n\t" + l.Class + " " + l.OldNum + " " + l.NewNum +
+				" " + l.Text
+		}
+	}
+	return
+}
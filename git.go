@@ -3,27 +3,248 @@ package main
 // Copyright ⓒ 2013 Alexander Bauer and Luke Evers (see LICENSE.md)
 
 import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
 	"os/exec"
+	"path"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 )
 
+type BlameLine struct {
+	SHA     string // Full SHA of the commit which last touched the line
+	Author  string // Author of that commit
+	Time    string // Relative time of that commit
+	Summary string // Subject of that commit
+	Text    string // Contents of the line
+}
+
+type Ref struct {
+	Name    string // Short name of the ref, such as "master"
+	SHA     string // Full SHA of the commit at the tip of the ref
+	Author  string // Author of that commit
+	Time    string // Relative time of that commit
+	Subject string // Subject of that commit
+	Base    string // Short name of the default branch
+	Ahead   int    // Commits in the ref which are not in Base
+	Behind  int    // Commits in Base which are not in the ref
+}
+
+type GrepFile struct {
+	File  string      // Path of the file within the repository
+	Lines []*GrepLine // Matching lines, and the context around them
+}
+
+type GrepLine struct {
+	Number int    // Line number in the file
+	Text   string // Contents of the line
+	Match  bool   // Whether the line matched, or is only context
+	Gap    bool   // Whether lines were skipped before this one
+}
+
+type TreeEntry struct {
+	Name string // Name of the file or directory
+	Path string // Full path within the repository
+	Type string // "blob", "tree", or "commit" for submodules
+	Mode string // File mode, such as "100644"
+	SHA  string // SHA of the object
+	Size int64  // Size of blobs in bytes, otherwise 0
+}
+
 type Commit struct {
-	SHA     string // Full SHA of the commit
-	Author  string // Author of the commit
-	Time    string // Relative time of the commit
-	Subject string // Subject of the commit
-	Body    string // Body of the commit
+	SHA            string    // Full SHA of the commit
+	Tree           string    // Full SHA of the commit's tree
+	Parents        []string  // Full SHAs of the parents of the commit
+	Author         string    // Author of the commit
+	AuthorEmail    string    // Email address of the author
+	AuthorDate     time.Time // Time at which the commit was authored
+	Committer      string    // Committer of the commit
+	CommitterEmail string    // Email address of the committer
+	CommitDate     time.Time // Time at which the commit was made
+	Time           string    // Relative time of the commit
+	Subject        string    // Subject of the commit
+	Body           string    // Body of the commit
 }
 
 const (
 	gitHttpBackend = "git-http-backend"
-	gitLogFmt      = "%H%n%cr%n%an%n%s%n%b"
-	gitLogSep      = "----GROVE-LOG-SEPARATOR----"
+	gitLogFmt      = "%H%x00%T%x00%P%x00%an%x00%ae%x00%aI%x00%cn%x00%ce%x00%cI%x00%cr%x00%s%x00%b"
+	gitLogFields   = 12 // Number of fields in gitLogFmt
+
+	// gitISODateFmt is the layout of git's strict ISO 8601 dates, as
+	// given by "%cI".
+	gitISODateFmt = "2006-01-02T15:04:05-07:00"
+
+	// gitRefFmt is used with 'git for-each-ref' to describe the
+	// commit at the tip of each ref, peeling annotated tags.
+	gitRefFmt = "%(refname:short)%09%(if)%(*objectname)%(then)" +
+		"%(*objectname)%09%(*authorname)%09%(*authordate:relative)%09%(*subject)" +
+		"%(else)" +
+		"%(objectname)%09%(authorname)%09%(authordate:relative)%09%(subject)" +
+		"%(end)"
 )
 
+var (
+	// ErrNotFound is given when a path does not exist at a ref.
+	ErrNotFound = errors.New("not found")
+
+	// ErrBadRef is given when a ref, SHA, or range does not name any
+	// commit in the repository.
+	ErrBadRef = errors.New("bad ref")
+
+	// ErrNotRepo is given when the directory is not a git
+	// repository.
+	ErrNotRepo = errors.New("not a git repository")
+
+	// ErrTimeout is given when git takes too long to answer.
+	ErrTimeout = errors.New("timed out")
+)
+
+// GitError describes a git command which failed. Its Kind is one of
+// the errors above, or nil if the failure could not be classified,
+// and can be checked with errors.Is.
+type GitError struct {
+	Kind    error    // Class of failure, if known
+	Args    []string // Arguments given to git
+	Status  int      // Exit status, or -1 if git did not exit
+	Message string   // Error output of git
+	Err     error    // Underlying error from running git
+}
+
+func (e *GitError) Error() string {
+	msg := e.Message
+	if len(msg) == 0 {
+		msg = e.Err.Error()
+	}
+	return "git " + strings.Join(e.Args, " ") + ": " + msg
+}
+
+// Unwrap allows errors.Is to match both the Kind and the underlying
+// error.
+func (e *GitError) Unwrap() []error {
+	return []error{e.Kind, e.Err}
+}
+
+// gitErrorMessages are the fragments of git's error messages which
+// identify each kind of failure, checked in order.
+var gitErrorMessages = []struct {
+	message string
+	kind    error
+}{
+	{"not a git repository", ErrNotRepo},
+	{"does not exist in", ErrNotFound},
+	{"exists on disk, but not in", ErrNotFound},
+	{"no such path", ErrNotFound},
+	{"invalid object name", ErrBadRef},
+	{"unknown revision", ErrBadRef},
+	{"bad revision", ErrBadRef},
+	{"bad object", ErrBadRef},
+	{"not a valid object name", ErrBadRef},
+	{"Needed a single revision", ErrBadRef},
+	{"ambiguous argument", ErrBadRef},
+	{"does not have any commits yet", ErrBadRef},
+}
+
+// gitStatus maps an error from the git layer to an HTTP status.
+func gitStatus(err error) int {
+	switch {
+	case err == nil:
+		return http.StatusOK
+	case errors.Is(err, ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrBadRef):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrTimeout):
+		return http.StatusGatewayTimeout
+	case errors.Is(err, ErrBusy):
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusInternalServerError
+}
+
 type git struct {
-	Path string // Directory path
+	Path string          // Directory path
+	ctx  context.Context // Context of the request, if any
+
+	repo     *repository       // Native reader, once opened
+	repoErr  error             // Reason the native reader cannot be used
+	resolved map[string]string // Full SHAs of refs, for cache keys
+}
+
+// native retrieves the native reader for the repository, opening it
+// the first time it is needed. If it cannot be used, it returns nil,
+// and git is run instead.
+func (g *git) native() *repository {
+	if g.repo == nil && g.repoErr == nil {
+		g.repo, g.repoErr = openRepository(g.Path)
+		if g.repo != nil {
+			g.repo.ctx = g.context()
+		}
+	}
+	return g.repo
+}
+
+// context retrieves the context of the request for which git is being
+// run. When it is done, any git commands still running are killed.
+func (g *git) context() context.Context {
+	if g.ctx == nil {
+		return context.Background()
+	}
+	return g.ctx
+}
+
+// isFinal checks whether an error from the native reader is one which
+// git would give as well, such as a timeout, in which case there is no
+// point running git.
+func isFinal(err error) bool {
+	return errors.Is(err, ErrTimeout) || errors.Is(err, context.Canceled)
+}
+
+// gitVersion is the version of git, as major, minor, and patch
+// numbers. It is read once, when grove starts.
+var gitVersion [3]int
+
+// readGitVersion sets gitVersion from the output of 'git --version',
+// which is of the form "git version 2.39.5", possibly followed by the
+// name of a build.
+func readGitVersion() error {
+	output, err := (&git{}).execute("--version")
+	if err != nil {
+		return err
+	}
+	fields := strings.Fields(output)
+	if len(fields) < 3 {
+		return errors.New("unexpected output from git --version: " + output)
+	}
+	for i, n := range strings.SplitN(fields[2], ".", 4) {
+		if i >= len(gitVersion) {
+			break
+		}
+		// Builds may add suffixes, as in "2.39.5.windows.1" or
+		// "2.40.0-rc1".
+		digits := strings.TrimRightFunc(n, func(c rune) bool {
+			return c < '0' || c > '9'
+		})
+		if gitVersion[i], err = strconv.Atoi(digits); err != nil {
+			return errors.New("unexpected git version " + fields[2])
+		}
+	}
+	return nil
+}
+
+// gitAtLeast checks whether git is at least the given version.
+func gitAtLeast(major, minor int) bool {
+	return gitVersion[0] > major ||
+		(gitVersion[0] == major && gitVersion[1] >= minor)
 }
 
 // Set a number of git variables.
@@ -43,43 +264,442 @@ func gitVarUser() (user string) {
 	return
 }
 
+// gitDescription retrieves the description of the repository at the
+// given path from .git/description, as used by gitweb. The default
+// placeholder left by 'git init' is treated as no description.
+func gitDescription(repository string) (description string) {
+	b, err := ioutil.ReadFile(path.Join(repository, ".git", "description"))
+	if err != nil {
+		return
+	}
+	description = strings.TrimSpace(string(b))
+	if strings.HasPrefix(description, "Unnamed repository;") {
+		description = ""
+	}
+	return
+}
+
+// ConfigBool checks whether the given key is set to true in the
+// repository's configuration, as interpreted by 'git config --bool'.
+func (g *git) ConfigBool(key string) bool {
+	value, err := g.execute("config", "--bool", "--get", key)
+	return err == nil && strings.TrimSpace(value) == "true"
+}
+
 func (g *git) Branch(ref string) (branch string) {
+	if r := g.native(); r != nil && ref == "HEAD" {
+		if target, ok := r.symbolicRef(ref); ok {
+			if strings.HasPrefix(target, "refs/heads/") {
+				return strings.TrimPrefix(target, "refs/heads/")
+			}
+		} else if _, err := r.resolve(ref); err == nil {
+			// HEAD is detached.
+			return ref
+		}
+	}
 	branch, _ = g.execute("rev-parse", "--abbrev-ref", ref)
 	return strings.TrimRight(branch, "\n")
 }
 
 // GetFile retrives the contents of a file from the repository. The
-// commit is either a SHA or pointer (such as HEAD, or HEAD^).
-func (g *git) GetFile(commit, file string) (contents []byte) {
-	contents, _ = g.executeB("--no-pager", "show", commit+":"+file)
-	return contents
+// commit is either a SHA or pointer (such as HEAD, or HEAD^). If the
+// path names a directory, rather than a file, it gives ErrNotFound.
+func (g *git) GetFile(commit, file string) (contents []byte, err error) {
+	key := g.cacheKey("file", commit, file)
+	if v, ok := queryCache.Get(key); ok {
+		return v.([]byte), nil
+	}
+	contents, err = g.getFile(commit, file)
+	if err == nil {
+		queryCache.Add(key, contents, len(contents))
+	}
+	return
+}
+
+func (g *git) getFile(commit, file string) (contents []byte, err error) {
+	if r := g.native(); r != nil {
+		contents, err := r.file(commit, file)
+		if err == nil || err == ErrNotFound {
+			return contents, err
+		}
+	}
+	// 'git show' lists the contents of a directory, rather than
+	// failing, so the type of the object is checked first.
+	typ, err := g.execute("cat-file", "-t", commit+":"+file)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(typ) != "blob" {
+		return nil, ErrNotFound
+	}
+	return g.executeB("--no-pager", "show", commit+":"+file)
 }
 
 // Retrieve a list of items in a directory from the repository. The
 // commit is either a SHA or a pointer (such as HEAD, or HEAD^).
-func (g *git) GetDir(commit, dir string) (files []string) {
-	output, _ := g.execute("--no-pager", "show", "--name-only", commit+":"+dir)
+func (g *git) GetDir(commit, dir string) (files []string, err error) {
+	key := g.cacheKey("dir", commit, dir)
+	if v, ok := queryCache.Get(key); ok {
+		return v.([]string), nil
+	}
+	files, err = g.getDir(commit, dir)
+	if err == nil {
+		queryCache.Add(key, files, sizeOfStrings(files))
+	}
+	return
+}
+
+func (g *git) getDir(commit, dir string) (files []string, err error) {
+	if r := g.native(); r != nil {
+		if entries, err := r.tree(commit, dir); err == nil {
+			// As with 'git show', directories are marked with a
+			// trailing slash.
+			for _, e := range entries {
+				if e.Type == "tree" {
+					files = append(files, e.Name+"/")
+				} else {
+					files = append(files, e.Name)
+				}
+			}
+			return files, nil
+		}
+	}
+	output, err := g.execute("--no-pager", "show", "--name-only", commit+":"+dir)
+	if err != nil {
+		return nil, err
+	}
 	parts := strings.SplitN(output, "\n\n", 2) // Split on the blank line
 	if len(parts) == 2 && strings.HasPrefix(parts[0], "tree") {
-		return strings.Split(strings.TrimRight(parts[1], "\n"), "\n")
+		return strings.Split(strings.TrimRight(parts[1], "\n"), "\n"), nil
+	}
+	// The path exists, but is not a directory.
+	return nil, ErrNotFound
+}
+
+// ListTree retrieves the entries of a directory in the repository at
+// the given commit, with their types, modes, and sizes. The top level
+// directory is given as an empty string.
+func (g *git) ListTree(commit, dir string) (entries []*TreeEntry, err error) {
+	key := g.cacheKey("tree", commit, dir)
+	if v, ok := queryCache.Get(key); ok {
+		return v.([]*TreeEntry), nil
+	}
+	entries, err = g.listTree(commit, dir)
+	if err != nil {
+		return nil, err
+	}
+	size := 0
+	for _, e := range entries {
+		size += 128 + len(e.Name) + len(e.Path)
 	}
+	queryCache.Add(key, entries, size)
 	return
 }
 
+func (g *git) listTree(commit, dir string) (entries []*TreeEntry, err error) {
+	if r := g.native(); r != nil {
+		if entries, err := r.tree(commit, dir); err == nil {
+			return entries, nil
+		}
+	}
+	args := []string{"ls-tree", "-l", "-z", commit}
+	if dir = strings.Trim(dir, "/"); len(dir) != 0 && dir != "." {
+		args = append(args, "--", dir+"/")
+	}
+	output, err := g.execute(args...)
+	if err != nil {
+		return nil, err
+	}
+	if len(output) == 0 && len(args) > 4 {
+		// Empty directories cannot be stored, so the directory does
+		// not exist.
+		return nil, ErrNotFound
+	}
+	for _, e := range strings.Split(output, "\x00") {
+		// Each entry is "<mode> <type> <sha> <size>\t<path>".
+		parts := strings.SplitN(e, "\t", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		fields := strings.Fields(parts[0])
+		if len(fields) != 4 {
+			continue
+		}
+		size, _ := strconv.ParseInt(fields[3], 10, 64)
+		entries = append(entries, &TreeEntry{
+			Name: path.Base(parts[1]),
+			Path: parts[1],
+			Type: fields[1],
+			Mode: fields[0],
+			SHA:  fields[2],
+			Size: size,
+		})
+	}
+	return entries, nil
+}
+
 // SHA retrieves the short form (minimum 8 characters) of the given
 // reference.
 func (g *git) SHA(ref string) (sha string) {
+	if r := g.native(); r != nil {
+		if sha, err := r.resolve(ref); err == nil {
+			return sha[:8]
+		}
+	}
 	commit, _ := g.execute("rev-parse", "--short=8", ref)
 	return strings.TrimRight(commit, "\n")
 }
 
+// FullSHA retrieves the full form of the given reference.
+func (g *git) FullSHA(ref string) (sha string) {
+	if r := g.native(); r != nil {
+		if c, err := r.commit(ref); err == nil {
+			return c.SHA
+		}
+	}
+	commit, _ := g.execute("rev-parse", "--verify", "-q", ref+"^{commit}")
+	return strings.TrimRight(commit, "\n")
+}
+
+// LastCommitTime retrieves the time of the commit at the given ref in
+// RFC 3339 format.
+func (g *git) LastCommitTime(ref string) (t string) {
+	if r := g.native(); r != nil {
+		if c, err := r.commit(ref); err == nil {
+			return c.CommitDate.Format(gitISODateFmt)
+		}
+	}
+	t, _ = g.execute("--no-pager", "log", "-1", "--format=format:%cI", ref)
+	return strings.TrimRight(t, "\n")
+}
+
 // Tags retrieves a list of all tag names from the repository.
 func (g *git) Tags() (tags []string) {
+	if r := g.native(); r != nil {
+		if names, err := r.refNames("refs/tags/"); err == nil {
+			for _, name := range names {
+				tags = append(tags, strings.TrimPrefix(name, "refs/tags/"))
+			}
+			return tags
+		}
+	}
 	t, _ := g.execute("tag", "--list")
-	return strings.Split(strings.TrimRight(t, "\n"), "\n")
+	for _, tag := range strings.Split(strings.TrimRight(t, "\n"), "\n") {
+		if len(tag) != 0 {
+			tags = append(tags, tag)
+		}
+	}
+	return
+}
+
+// RefNames retrieves the short names of all branches and tags in the
+// repository.
+func (g *git) RefNames() (names []string) {
+	if r := g.native(); r != nil {
+		heads, err := r.refNames("refs/heads/")
+		tags, tagErr := r.refNames("refs/tags/")
+		if err == nil && tagErr == nil {
+			for _, name := range append(heads, tags...) {
+				name = strings.TrimPrefix(name, "refs/heads/")
+				names = append(names, strings.TrimPrefix(name, "refs/tags/"))
+			}
+			return names
+		}
+	}
+	output, _ := g.execute("for-each-ref", "--format=%(refname:short)",
+		"refs/heads", "refs/tags")
+	for _, n := range strings.Split(strings.TrimRight(output, "\n"), "\n") {
+		if len(n) != 0 {
+			names = append(names, n)
+		}
+	}
+	return
+}
+
+// Refs retrieves every ref under the given prefix, such as
+// "refs/heads" or "refs/tags", most recent first. Each is compared to
+// the default branch to find how far ahead and behind it is.
+func (g *git) Refs(prefix string) (refs []*Ref, err error) {
+	base := g.DefaultBranch()
+	baseSHA := g.FullSHA(base)
+	format := gitRefFmt
+	if gitAtLeast(2, 41) {
+		// All of the counts are made in a single walk.
+		format += "%09%(ahead-behind:" + base + ")"
+	}
+	output, err := g.execute("for-each-ref", "--sort=-creatordate",
+		"--format="+format, prefix)
+	if err != nil {
+		return nil, err
+	}
+	for _, l := range strings.Split(strings.TrimRight(output, "\n"), "\n") {
+		fields := strings.Split(l, "\t")
+		if len(fields) < 5 {
+			continue
+		}
+		ref := &Ref{
+			Name:    fields[0],
+			SHA:     fields[1],
+			Author:  fields[2],
+			Time:    fields[3],
+			Subject: strings.Join(fields[4:], "\t"),
+			Base:    shortRef(base),
+		}
+		if gitAtLeast(2, 41) && len(fields) > 5 {
+			ref.Subject = strings.Join(fields[4:len(fields)-1], "\t")
+			counts := strings.Fields(fields[len(fields)-1])
+			if len(counts) == 2 {
+				ref.Ahead, _ = strconv.Atoi(counts[0])
+				ref.Behind, _ = strconv.Atoi(counts[1])
+			}
+		} else if len(baseSHA) != 0 {
+			ref.Ahead, ref.Behind = g.AheadBehind(baseSHA, ref.SHA)
+		}
+		refs = append(refs, ref)
+	}
+	return refs, nil
+}
+
+// DefaultBranch retrieves the full name of the branch against which
+// others are compared. This is the one named by origin's HEAD, if the
+// repository is a clone, or that named by HEAD in a bare repository,
+// or otherwise that given by init.defaultBranch. If it does not exist,
+// HEAD is used instead.
+func (g *git) DefaultBranch() string {
+	exists := func(ref string) bool {
+		return len(g.FullSHA(ref)) != 0
+	}
+	if remote := g.symbolicRef("refs/remotes/origin/HEAD"); len(remote) != 0 {
+		// The local branch of the same name is preferred, as it is
+		// the one which the owner works on.
+		local := "refs/heads/" + strings.TrimPrefix(remote, "refs/remotes/origin/")
+		if exists(local) {
+			return local
+		}
+		if exists(remote) {
+			return remote
+		}
+	}
+	if bare, _ := g.execute("rev-parse", "--is-bare-repository"); strings.TrimSpace(bare) == "true" {
+		if head := g.symbolicRef("HEAD"); len(head) != 0 && exists(head) {
+			return head
+		}
+	}
+	name, _ := g.execute("config", "--get", "init.defaultBranch")
+	if name = strings.TrimSpace(name); len(name) == 0 {
+		name = "master"
+	}
+	if exists("refs/heads/" + name) {
+		return "refs/heads/" + name
+	}
+	return "HEAD"
+}
+
+// symbolicRef retrieves the full name of the ref to which a symbolic
+// ref points, or an empty string if it is not symbolic.
+func (g *git) symbolicRef(name string) string {
+	if r := g.native(); r != nil {
+		target, _ := r.symbolicRef(name)
+		return target
+	}
+	target, _ := g.execute("symbolic-ref", "-q", name)
+	return strings.TrimSpace(target)
+}
+
+// shortRef shortens the full name of a ref, as git does for
+// %(refname:short).
+func shortRef(name string) string {
+	for _, prefix := range []string{"refs/heads/", "refs/tags/", "refs/remotes/"} {
+		if strings.HasPrefix(name, prefix) {
+			return strings.TrimPrefix(name, prefix)
+		}
+	}
+	return name
+}
+
+// AheadBehind counts the commits in ref which are not in base, and
+// those in base which are not in ref. Both should be full SHAs, so
+// that the counts, which then never change, can be cached.
+func (g *git) AheadBehind(base, ref string) (ahead, behind int) {
+	key := ""
+	if queryCache != nil {
+		key = strings.Join([]string{g.Path, "aheadbehind", base, ref}, "\x00")
+	}
+	if v, ok := queryCache.Get(key); ok {
+		counts := v.([2]int)
+		return counts[0], counts[1]
+	}
+	output, err := g.execute("rev-list", "--left-right", "--count",
+		base+"..."+ref)
+	fields := strings.Fields(output)
+	if err != nil || len(fields) != 2 {
+		return
+	}
+	behind, _ = strconv.Atoi(fields[0])
+	ahead, _ = strconv.Atoi(fields[1])
+	queryCache.Add(key, [2]int{ahead, behind}, len(key))
+	return
 }
 
 func (g *git) TotalCommits() (commits int) {
+	// Every ref is counted from, so the result is cached for as long
+	// as none of them move.
+	key := ""
+	if refs := g.refsState(); len(refs) != 0 && queryCache != nil {
+		key = strings.Join([]string{g.Path, "total", refs}, "\x00")
+	}
+	if v, ok := queryCache.Get(key); ok {
+		return v.(int)
+	}
+	commits = g.totalCommits()
+	queryCache.Add(key, commits, len(key))
+	return
+}
+
+// refsState retrieves a description of every ref in the repository
+// and HEAD, which changes whenever any of them move.
+func (g *git) refsState() string {
+	if r := g.native(); r != nil {
+		refs, err := r.allRefs()
+		head, headErr := r.resolve("HEAD")
+		if err == nil && headErr == nil {
+			names := make([]string, 0, len(refs))
+			for name := range refs {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			h := sha1.New()
+			io.WriteString(h, head)
+			for _, name := range names {
+				io.WriteString(h, "\n"+refs[name]+" "+name)
+			}
+			return fmt.Sprintf("%x", h.Sum(nil))
+		}
+	}
+	output, err := g.execute("show-ref", "--head")
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("%x", sha1.Sum([]byte(output)))
+}
+
+func (g *git) totalCommits() (commits int) {
+	if r := g.native(); r != nil {
+		// As with 'git rev-list --all', every ref and HEAD is walked.
+		revs, err := r.refNames("refs/")
+		if err == nil {
+			err = r.log(append(revs, "HEAD"), func(*Commit) bool {
+				commits++
+				return true
+			})
+		}
+		if err == nil {
+			return commits
+		} else if isFinal(err) {
+			return 0
+		}
+		commits = 0
+	}
 	c, _ := g.execute("rev-list", "--all")
 	return len(strings.Split(strings.TrimRight(c, "\n"), "\n"))
 }
@@ -89,96 +709,515 @@ func (g *git) RefExists(ref string) (exists bool) {
 	// the ref does not exist in the repository. Cmd.Output(), which
 	// is used by execute(), uses Cmd.Run(), which returns an error if
 	// an exit status other than 0 is returned.
+	if r := g.native(); r != nil {
+		_, headErr := r.commit("HEAD")
+		if _, err := r.commit(ref); err == nil && headErr == nil {
+			return true
+		}
+	}
 	_, err := g.execute("rev-list", "HEAD.."+ref)
 	return err == nil
 }
 
+// unborn checks whether an error came from asking for the history of
+// HEAD in a repository which has no commits yet. That history is
+// empty, rather than an error.
+func (g *git) unborn(ref string, err error) bool {
+	return ref == "HEAD" && errors.Is(err, ErrBadRef) && len(g.FullSHA("HEAD")) == 0
+}
+
+// Diff retrieves the unified diff introduced by the given commit,
+// with renames detected. Merge commits produce a combined diff.
+func (g *git) Diff(ref string) (diff string, err error) {
+	return g.execute("--no-pager", "show", "--no-color", "-M",
+		"--format=format:", ref)
+}
+
+// DiffRange retrieves the unified diff between the common ancestor of
+// base and head, and head itself. This is the change which merging
+// head into base would introduce.
+func (g *git) DiffRange(base, head string) (diff string, err error) {
+	return g.execute("--no-pager", "diff", "--no-color", "-M",
+		base+"..."+head)
+}
+
+// Archive writes an archive of the tree at the given ref to w as it
+// is produced. The format may be any supported by 'git archive', such
+// as "tar.gz" or "zip", and every path in the archive is prefixed
+// with prefix.
+//
+// Archives of large repositories may take a long time to download, so
+// the timeout on git commands does not apply, but git is still killed
+// if the request is cancelled.
+func (g *git) Archive(w io.Writer, ref, format, prefix string) (err error) {
+	release, err := gitLimiter.AcquireTransfer(g.context())
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	cmd := g.command(g.context(), "archive", "--format="+format,
+		"--prefix="+prefix, ref)
+	cmd.Stdout = w
+	return cmd.Run()
+}
+
+// Grep searches the tree at the given ref for lines containing query,
+// ignoring case and binary files. Each match is surrounded by the
+// given number of lines of context. At most max matching lines are
+// retrieved, unless max is 0, after which git is stopped, and
+// truncated is true.
+func (g *git) Grep(ref, query string, context, max int) (files []*GrepFile, truncated bool, err error) {
+	// With -z, each line is given as <ref>:<file>\0<line>\0<text>,
+	// and groups are separated by "--". Because the separators no
+	// longer distinguish matches from context, each line is checked
+	// again here.
+	lower := strings.ToLower(query)
+	var f *GrepFile
+	gap := false
+	matches := 0
+	err = g.executeLines([]string{"--no-pager", "grep", "-z", "-n", "-I",
+		"-F", "-i", "-C", strconv.Itoa(context), "-e", query, ref, "--"},
+		'\n', func(l string) bool {
+			l = strings.TrimSuffix(l, "\r")
+			if l == "--" {
+				gap = true
+				// Once there are enough matches, the context which
+				// follows the last is still included.
+				return !truncated
+			}
+			parts := strings.SplitN(l, "\x00", 3)
+			if len(parts) != 3 {
+				return true
+			}
+			name := strings.TrimPrefix(parts[0], ref+":")
+			match := strings.Contains(strings.ToLower(parts[2]), lower)
+			if truncated && (match || f.File != name) {
+				return false
+			}
+			n, _ := strconv.Atoi(parts[1])
+			if f == nil || f.File != name {
+				f = &GrepFile{File: name}
+				files = append(files, f)
+				gap = false
+			}
+			f.Lines = append(f.Lines, &GrepLine{
+				Number: n,
+				Text:   parts[2],
+				Match:  match,
+				Gap:    gap,
+			})
+			gap = false
+			if match {
+				matches++
+				truncated = max > 0 && matches >= max
+			}
+			return true
+		})
+	if err != nil {
+		// 'git grep' exits with status 1, and says nothing, if there
+		// are no matches.
+		if e, ok := err.(*GitError); ok && e.Status == 1 && len(e.Message) == 0 {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	return files, truncated, nil
+}
+
+// Blame retrieves, for each line of a file at the given commit, the
+// commit which last modified it. It is parsed from the porcelain
+// format of 'git blame', in which the details of each commit are only
+// given the first time it appears.
+func (g *git) Blame(ref, file string) (lines []*BlameLine, err error) {
+	output, err := g.execute("--no-pager", "blame", "--porcelain", ref, "--", file)
+	if err != nil {
+		return nil, err
+	}
+
+	// commits holds the details of each commit seen so far, and
+	// current is the commit whose lines are being read. Every group
+	// begins with a header line naming the commit, so one is expected
+	// at the start and after each line of the file.
+	commits := make(map[string]*BlameLine)
+	var current *BlameLine
+	header := true
+	for _, l := range strings.Split(output, "\n") {
+		switch {
+		case header:
+			fields := strings.Fields(l)
+			if len(fields) == 0 {
+				continue
+			}
+			sha := fields[0]
+			if commits[sha] == nil {
+				commits[sha] = &BlameLine{SHA: sha}
+			}
+			current = commits[sha]
+			header = false
+		case strings.HasPrefix(l, "\t"):
+			// Lines of the file are prefixed with a tab, and end the
+			// group.
+			line := *current
+			line.Text = l[1:]
+			lines = append(lines, &line)
+			header = true
+		case strings.HasPrefix(l, "author "):
+			current.Author = strings.TrimPrefix(l, "author ")
+		case strings.HasPrefix(l, "author-time "):
+			t, err := strconv.ParseInt(strings.TrimPrefix(l, "author-time "), 10, 64)
+			if err == nil {
+				current.Time = relativeTime(time.Unix(t, 0))
+			}
+		case strings.HasPrefix(l, "summary "):
+			current.Summary = strings.TrimPrefix(l, "summary ")
+		}
+	}
+	return lines, nil
+}
+
 // Commits parses the log and returns an array of Commit types, up to
 // the given max.
-func (g *git) Commits(ref string, max int) (commits []*Commit) {
+func (g *git) Commits(ref string, max int) (commits []*Commit, err error) {
+	key := g.cacheKey("log", ref, strconv.Itoa(max))
+	if v, ok := queryCache.Get(key); ok {
+		return freshCommits(v.([]*Commit)), nil
+	}
+	commits, err = g.commits(ref, max)
+	if err == nil {
+		queryCache.Add(key, commits, sizeOfCommits(commits))
+	} else if g.unborn(ref, err) {
+		return nil, nil
+	}
+	return
+}
+
+func (g *git) commits(ref string, max int) (commits []*Commit, err error) {
+	if r := g.native(); r != nil {
+		commits, err := r.commits(ref, max)
+		if err == nil || isFinal(err) {
+			return commits, err
+		}
+	}
 	var log string
 	if max > 0 {
-		log, _ = g.execute("--no-pager", "log", "--format=format:"+gitLogFmt+gitLogSep, ref, "-n "+strconv.Itoa(max))
+		log, err = g.execute("--no-pager", "log", "-z", "--format=format:"+gitLogFmt, ref, "-n "+strconv.Itoa(max))
 	} else {
-		log, _ = g.execute("--no-pager", "log", "--format=format:"+gitLogFmt+gitLogSep, ref)
+		log, err = g.execute("--no-pager", "log", "-z", "--format=format:"+gitLogFmt, ref)
 	}
-	commitLogs := strings.Split(log, gitLogSep)
-	commits = make([]*Commit, 0, len(commitLogs))
-	for _, l := range commitLogs {
-		commit := gitParseCommit(strings.Split(l, "\n"))
-		if commit != nil {
-			commits = append(commits, commit)
-		}
-	}
-	return
+	return gitParseLog(log), err
 }
 
 // CommitsByFile retrieves a list of commits which modify or otherwise
 // affect a file, up to the given maximum number of commits.
-func (g *git) CommitsByFile(ref, file string, max int) (commits []*Commit) {
+func (g *git) CommitsByFile(ref, file string, max int) (commits []*Commit, err error) {
+	key := g.cacheKey("log", ref, strconv.Itoa(max), file)
+	if v, ok := queryCache.Get(key); ok {
+		return freshCommits(v.([]*Commit)), nil
+	}
+	commits, err = g.commitsByFile(ref, file, max)
+	if err == nil {
+		queryCache.Add(key, commits, sizeOfCommits(commits))
+	} else if g.unborn(ref, err) {
+		return nil, nil
+	}
+	return
+}
+
+func (g *git) commitsByFile(ref, file string, max int) (commits []*Commit, err error) {
 	var log string
 	if max > 0 {
-		log, _ = g.execute("--no-pager", "log", ref, "--follow", "--format=format:"+gitLogFmt+gitLogSep, "-n "+strconv.Itoa(max), "--", file)
+		log, err = g.execute("--no-pager", "log", "-z", ref, "--follow", "--format=format:"+gitLogFmt, "-n "+strconv.Itoa(max), "--", file)
 	} else {
-		log, _ = g.execute("--no-pager", "log", ref, "--follow", "--format=format:"+gitLogFmt+gitLogSep, "--", file)
+		log, err = g.execute("--no-pager", "log", "-z", ref, "--follow", "--format=format:"+gitLogFmt, "--", file)
 	}
-	commitLogs := strings.Split(log, gitLogSep)
-	commits = make([]*Commit, 0, len(commitLogs))
-	for _, l := range commitLogs {
-		commit := gitParseCommit(strings.Split(l, "\n"))
-		if commit != nil {
-			commits = append(commits, commit)
+	return gitParseLog(log), err
+}
+
+// CommitsBySHA retrieves the given commits, in the order given.
+// Commits are cached individually, so only those which have not been
+// seen recently are looked up.
+func (g *git) CommitsBySHA(shas []string) (commits []*Commit, err error) {
+	if len(shas) == 0 {
+		return
+	}
+	if queryCache == nil {
+		return g.commitsBySHA(shas)
+	}
+	commits = make([]*Commit, len(shas))
+	var missing []string
+	for i, sha := range shas {
+		if v, ok := queryCache.Get(g.Path + "\x00commit\x00" + sha); ok {
+			commits[i] = freshCommits([]*Commit{v.(*Commit)})[0]
+		} else {
+			missing = append(missing, sha)
+		}
+	}
+	if len(missing) == 0 {
+		return
+	}
+	found, err := g.commitsBySHA(missing)
+	if err != nil {
+		return nil, err
+	}
+	if len(found) != len(missing) {
+		// Some could not be found, so the results cannot be matched
+		// up with the SHAs which were asked for.
+		return found, nil
+	}
+	for i, j := 0, 0; i < len(commits); i++ {
+		if commits[i] != nil {
+			continue
 		}
+		commits[i] = found[j]
+		queryCache.Add(g.Path+"\x00commit\x00"+found[j].SHA, found[j],
+			sizeOfCommit(found[j]))
+		j++
 	}
 	return
 }
 
-// gitParseCommit is a low-level utility for parsing log formats of
-// the following format. They are generated like this by gitLogFmt.
-//    <full hash>
-//    <commit time relative>
-//    <author name>
-//    <nonwrapped commit message>
-func gitParseCommit(log []string) (commit *Commit) {
-	var sha string
-	var time string
-	var author string
-	var subject string
-	var body string
+func (g *git) commitsBySHA(shas []string) (commits []*Commit, err error) {
+	if r := g.native(); r != nil {
+		for _, sha := range shas {
+			c, err := r.commit(sha)
+			if err != nil {
+				commits = nil
+				break
+			}
+			commits = append(commits, c)
+		}
+		if commits != nil {
+			return commits, nil
+		}
+	}
+	args := []string{"--no-pager", "log", "-z", "--no-walk=unsorted",
+		"--format=format:" + gitLogFmt}
+	log, err := g.execute(append(args, shas...)...)
+	return gitParseLog(log), err
+}
 
-	for _, l := range log {
-		if len(sha) == 0 {
-			// If l is empty, then this will be run again.
-			sha = l
-			continue
+// HistoryPage retrieves a page of at most n commits from the history
+// of ref, newest first. If file is given, only commits which affect it
+// are included, following renames. Commits may also be limited to
+// those by a particular author, or made within a range of dates, in
+// any format accepted by 'git log --since'. Empty arguments are
+// ignored.
+//
+// The page begins after the commit given by after, or ends before the
+// one given by before, either of which may be abbreviated. If neither
+// is given, or the commit is not in the history, the page begins at
+// the start. History is only walked as far as the end of the page, so
+// that the cost of a page does not grow with the size of the
+// repository. It also reports whether there are newer and older
+// commits beyond the page.
+func (g *git) HistoryPage(ref, file, author, since, until, after, before string,
+	n int) (commits []*Commit, newer, older bool, err error) {
+	var first, page []string
+	found := false
+	seen := 0
+	err = g.history(ref, file, author, since, until, func(sha string) bool {
+		if len(first) <= n {
+			first = append(first, sha)
 		}
-		if len(time) == 0 {
-			time = l
-			continue
+		switch {
+		case len(after) != 0 && !found:
+			found = strings.HasPrefix(sha, after)
+		case len(after) != 0:
+			page = append(page, sha)
+			return len(page) <= n
+		case len(before) != 0 && seen == 0 && strings.HasPrefix(sha, before):
+			// Nothing is newer than the first commit, so the page
+			// begins at the start instead.
+			before = ""
+			return len(first) <= n
+		case len(before) != 0:
+			if strings.HasPrefix(sha, before) {
+				// Only the last n commits before the cursor are
+				// kept, as it is walked towards.
+				found, newer, older = true, seen > n, true
+				return false
+			}
+			if page = append(page, sha); len(page) > n {
+				page = page[1:]
+			}
+		default:
+			return len(first) <= n
 		}
-		if len(author) == 0 {
-			author = l
-			continue
+		seen++
+		return true
+	})
+	if err != nil {
+		if g.unborn(ref, err) {
+			err = nil
 		}
-		if len(subject) == 0 {
-			subject = l
-			continue
+		return nil, false, false, err
+	}
+
+	switch {
+	case !found:
+		page, newer = first, false
+		fallthrough
+	case len(after) != 0:
+		newer = newer || found
+		if len(page) > n {
+			page, older = page[:n], true
 		}
+	}
+	commits, err = g.CommitsBySHA(page)
+	return commits, newer, older, err
+}
 
-		body += l + "\n"
+// history walks the history of ref, filtered as for HistoryPage, and
+// calls visit with the SHA of each commit, newest first, until it
+// returns false.
+func (g *git) history(ref, file, author, since, until string, visit func(sha string) bool) error {
+	// If the native reader fails part of the way, such as at the edge
+	// of a shallow clone, git continues from where it stopped, as both
+	// walk the history in the same order.
+	skip := 0
+	if r := g.native(); r != nil && len(file)+len(author)+len(since)+len(until) == 0 {
+		err := r.log([]string{ref}, func(c *Commit) bool {
+			skip++
+			return visit(c.SHA)
+		})
+		if err == nil || isFinal(err) {
+			return err
+		}
+	}
+	args := []string{"--no-pager", "log", "--format=format:%H", ref}
+	if len(author) != 0 {
+		args = append(args, "--author="+author)
+	}
+	if len(since) != 0 {
+		args = append(args, "--since="+since)
+	}
+	if len(until) != 0 {
+		args = append(args, "--until="+until)
+	}
+	if len(file) != 0 {
+		args = append(args, "--follow", "--", file)
+	} else {
+		args = append(args, "--")
 	}
+	return g.executeLines(args, '\n', func(sha string) bool {
+		if skip > 0 {
+			skip--
+			return true
+		}
+		return visit(sha)
+	})
+}
 
-	commit = &Commit{
-		SHA:     sha,
-		Time:    time,
-		Author:  author,
-		Subject: subject,
-		Body:    body,
+// CountCommits retrieves the number of commits reachable from ref. It
+// is cached for as long as ref does not move.
+func (g *git) CountCommits(ref string) (count int, err error) {
+	key := g.cacheKey("count", ref)
+	if v, ok := queryCache.Get(key); ok {
+		return v.(int), nil
+	}
+	output, err := g.execute("rev-list", "--count", ref, "--")
+	if err != nil {
+		if g.unborn(ref, err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	count, err = strconv.Atoi(strings.TrimSpace(output))
+	if err == nil {
+		queryCache.Add(key, count, len(key))
+	}
+	return
+}
+
+// gitParseLog splits a log generated with gitLogFmt and the -z flag
+// into individual commits, and parses each. Because every field is
+// terminated by a NUL, which cannot appear in a commit, and the log
+// itself separates commits with a NUL, each commit is simply the next
+// gitLogFields fields.
+func gitParseLog(log string) (commits []*Commit) {
+	if len(log) == 0 {
+		return
+	}
+	fields := strings.Split(log, "\x00")
+	commits = make([]*Commit, 0, len(fields)/gitLogFields)
+	for len(fields) >= gitLogFields {
+		commit := gitParseCommit(fields[:gitLogFields])
+		if commit != nil {
+			commits = append(commits, commit)
+		}
+		fields = fields[gitLogFields:]
+	}
+	return
+}
+
+// gitParseCommit is a low-level utility for parsing the fields of a
+// single commit, in the order given by gitLogFmt:
+//
+//	<full hash>
+//	<tree hash>
+//	<parent hashes, separated by spaces>
+//	<author name>
+//	<author email>
+//	<author date, strict ISO 8601>
+//	<committer name>
+//	<committer email>
+//	<committer date, strict ISO 8601>
+//	<commit time relative>
+//	<subject>
+//	<body>
+//
+// If the commit has no hash, it returns nil.
+func gitParseCommit(fields []string) (commit *Commit) {
+	if len(fields) != gitLogFields || len(fields[0]) == 0 {
+		return nil
 	}
 
+	commit = &Commit{
+		SHA:            fields[0],
+		Tree:           fields[1],
+		Parents:        strings.Fields(fields[2]),
+		Author:         fields[3],
+		AuthorEmail:    fields[4],
+		Committer:      fields[6],
+		CommitterEmail: fields[7],
+		Time:           fields[9],
+		Subject:        fields[10],
+		Body:           fields[11],
+	}
+	commit.AuthorDate, _ = time.Parse(time.RFC3339, fields[5])
+	commit.CommitDate, _ = time.Parse(time.RFC3339, fields[8])
 	return
 }
 
+// relativeTime formats the time since t in the same way as git's
+// relative dates (such as in "%cr"), for example "3 hours ago".
+func relativeTime(t time.Time) string {
+	d := time.Since(t)
+	var n int64
+	var unit string
+	switch {
+	case d < 90*time.Second:
+		n, unit = int64(d/time.Second), "second"
+	case d < 90*time.Minute:
+		n, unit = int64((d+30*time.Second)/time.Minute), "minute"
+	case d < 36*time.Hour:
+		n, unit = int64((d+30*time.Minute)/time.Hour), "hour"
+	case d < 14*24*time.Hour:
+		n, unit = int64((d+12*time.Hour)/(24*time.Hour)), "day"
+	case d < 10*7*24*time.Hour:
+		n, unit = int64((d+3*24*time.Hour)/(7*24*time.Hour)), "week"
+	case d < 365*24*time.Hour:
+		n, unit = int64((d+15*24*time.Hour)/(30*24*time.Hour)), "month"
+	default:
+		n, unit = int64((d+182*24*time.Hour)/(365*24*time.Hour)), "year"
+	}
+	if n != 1 {
+		unit += "s"
+	}
+	return fmt.Sprintf("%d %s ago", n, unit)
+}
+
 // execute invokes exec.Command() with the given command, arguments,
 // and working directory. All CR ('\r') characters are removed in
 // output.
@@ -187,11 +1226,125 @@ func (g *git) execute(args ...string) (output string, err error) {
 	return string(out), err
 }
 
+// executeLines is as execute, but calls fn with each line of the
+// output, without the separator, as git produces it. If fn returns
+// false, git is stopped, so that only as much of the output as is
+// needed is ever produced.
+func (g *git) executeLines(args []string, sep byte, fn func(line string) bool) error {
+	ctx := g.context()
+	release, err := gitLimiter.Acquire(ctx, g.Path)
+	if err != nil {
+		return newGitError(ctx, args, err)
+	}
+	defer release()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	if *fGitTimeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, *fGitTimeout)
+		defer cancel()
+	}
+	cmd := g.command(ctx, args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return newGitError(ctx, args, err)
+	}
+	if err = cmd.Start(); err != nil {
+		return newGitError(ctx, args, err)
+	}
+
+	br := bufio.NewReader(stdout)
+	for {
+		line, readErr := br.ReadString(sep)
+		line = strings.TrimSuffix(line, string(sep))
+		if len(line) != 0 && !fn(line) {
+			// The rest of the output is not wanted.
+			cancel()
+			cmd.Wait()
+			return nil
+		}
+		if readErr != nil {
+			break
+		}
+	}
+	if err = cmd.Wait(); err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			exitErr.Stderr = stderr.Bytes()
+		}
+		return newGitError(ctx, args, err)
+	}
+	return nil
+}
+
+// executeB is as execute, but returns the output as bytes. If git
+// fails, the error is a *GitError describing why. Each command is
+// limited to the duration given by -git-timeout, and is killed if it
+// takes longer, or if the request is cancelled. If too many git
+// processes are already running, it waits for one to finish first.
 func (g *git) executeB(args ...string) (output []byte, err error) {
-	cmd := exec.Command("git", args...)
+	ctx := g.context()
+	release, err := gitLimiter.Acquire(ctx, g.Path)
+	if err != nil {
+		return nil, newGitError(ctx, args, err)
+	}
+	defer release()
+
+	if timeout := setting(fGitTimeout); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	out, err := g.command(ctx, args...).Output()
+	if err != nil {
+		return out, newGitError(ctx, args, err)
+	}
+	return out, nil
+}
+
+// command prepares git to be run in the repository with the given
+// arguments. When ctx is done, git is killed, along with any
+// processes it has started.
+func (g *git) command(ctx context.Context, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, "git", args...)
 	if len(g.Path) != 0 {
 		cmd.Dir = g.Path
 	}
-	out, err := cmd.Output()
-	return out, err
+	setProcessGroup(cmd)
+
+	// Output is not waited for indefinitely once git is killed, in
+	// case something else is still holding its pipes open.
+	cmd.WaitDelay = time.Second
+	return cmd
+}
+
+// newGitError describes the failure of a git command, classifying it
+// by the message which git gave, or by the reason ctx was done.
+func newGitError(ctx context.Context, args []string, err error) *GitError {
+	e := &GitError{
+		Args:   args,
+		Status: -1,
+		Err:    err,
+	}
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		e.Status = exitErr.ExitCode()
+		e.Message = strings.TrimSpace(string(exitErr.Stderr))
+	}
+	for _, c := range gitErrorMessages {
+		if strings.Contains(e.Message, c.message) {
+			e.Kind = c.kind
+			break
+		}
+	}
+	if err == ErrBusy {
+		e.Kind = ErrBusy
+	}
+	switch ctx.Err() {
+	case context.DeadlineExceeded:
+		e.Kind, e.Err = ErrTimeout, ctx.Err()
+	case context.Canceled:
+		e.Err = ctx.Err()
+	}
+	return e
 }
@@ -0,0 +1,94 @@
+package main
+
+// Copyright ⓒ 2013 Alexander Bauer and Luke Evers (see LICENSE.md)
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGitParseLog(t *testing.T) {
+	// logEntry gives the fields of a commit in the order of
+	// gitLogFmt.
+	logEntry := func(sha, parents, author, subject, body string) string {
+		return strings.Join([]string{
+			sha, "tree" + sha, parents,
+			author, author + "@example.com", "2013-06-01T12:00:00+02:00",
+			"Committer", "committer@example.com", "2013-06-02T08:30:00Z",
+			"3 days ago", subject, body,
+		}, "\x00")
+	}
+	commit := func(sha string, parents []string, author, subject, body string) *Commit {
+		return &Commit{
+			SHA:            sha,
+			Tree:           "tree" + sha,
+			Parents:        parents,
+			Author:         author,
+			AuthorEmail:    author + "@example.com",
+			AuthorDate:     time.Date(2013, 6, 1, 12, 0, 0, 0, time.FixedZone("", 2*60*60)),
+			Committer:      "Committer",
+			CommitterEmail: "committer@example.com",
+			CommitDate:     time.Date(2013, 6, 2, 8, 30, 0, 0, time.UTC),
+			Time:           "3 days ago",
+			Subject:        subject,
+			Body:           body,
+		}
+	}
+
+	tests := []struct {
+		name string
+		log  string
+		want []*Commit
+	}{{
+		name: "empty",
+		log:  "",
+	}, {
+		name: "root commit",
+		log:  logEntry("a1", "", "alice", "Initial commit", ""),
+		want: []*Commit{commit("a1", []string{}, "alice", "Initial commit", "")},
+	}, {
+		name: "several",
+		log: logEntry("c3", "b2 d4", "bob", "Merge branch 'side'", "") + "\x00" +
+			logEntry("b2", "a1", "alice", "Fix", "Longer\n\nexplanation\n") + "\x00" +
+			logEntry("a1", "", "alice", "Initial commit", ""),
+		want: []*Commit{
+			commit("c3", []string{"b2", "d4"}, "bob", "Merge branch 'side'", ""),
+			commit("b2", []string{"a1"}, "alice", "Fix", "Longer\n\nexplanation\n"),
+			commit("a1", []string{}, "alice", "Initial commit", ""),
+		},
+	}, {
+		name: "no hash",
+		log: logEntry("", "", "alice", "Nothing", "") + "\x00" +
+			logEntry("a1", "", "alice", "Initial commit", ""),
+		want: []*Commit{commit("a1", []string{}, "alice", "Initial commit", "")},
+	}, {
+		name: "incomplete",
+		log: logEntry("b2", "a1", "alice", "Fix", "") + "\x00" +
+			"a1\x00treea1\x00",
+		want: []*Commit{commit("b2", []string{"a1"}, "alice", "Fix", "")},
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := gitParseLog(test.log)
+			if len(got) != len(test.want) {
+				t.Fatalf("got %d commits, want %d", len(got), len(test.want))
+			}
+			for i := range got {
+				if !got[i].AuthorDate.Equal(test.want[i].AuthorDate) ||
+					!got[i].CommitDate.Equal(test.want[i].CommitDate) {
+					t.Errorf("commit %d: got dates %v and %v, want %v and %v",
+						i, got[i].AuthorDate, got[i].CommitDate,
+						test.want[i].AuthorDate, test.want[i].CommitDate)
+				}
+				g, w := *got[i], *test.want[i]
+				g.AuthorDate, g.CommitDate = time.Time{}, time.Time{}
+				w.AuthorDate, w.CommitDate = time.Time{}, time.Time{}
+				if !reflect.DeepEqual(g, w) {
+					t.Errorf("commit %d: got %+v, want %+v", i, g, w)
+				}
+			}
+		})
+	}
+}
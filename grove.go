@@ -7,9 +7,10 @@ import (
 	"flag"
 	"fmt"
 	"log"
-	"net/http/cgi"
 	"os"
 	"path"
+	"runtime"
+	"time"
 )
 
 var (
@@ -22,19 +23,42 @@ var (
 )
 
 var (
-	l       *log.Logger
-	handler *cgi.Handler
+	l *log.Logger
 )
 
 const (
-	usage = "usage: %s [repositorydir]\n"
+	usage = "usage: %s [repositorydir...]\n       %s config check [file]\n"
 )
 
 var (
-	fBind = flag.String("bind", Bind, "interface to bind to")
+	fBind = flag.String("bind", Bind, "interface to bind to, or unix:<path> for a Unix socket")
 	fPort = flag.String("port", Port, "port to listen on")
 	fRes  = flag.String("res", Resources, "resources directory")
 
+	fConfig    = flag.String("config", "", "configuration file (default ~/.config/grove/config)")
+	fTemplates = flag.String("templates", "", "templates directory, if not the one in the resources directory")
+	fPolicy    = flag.String("policy", "mode", "which repositories to serve: mode, glob, marker, or gitconfig")
+	fPerms     = flag.String("perms", "world", "who must be able to read a directory to serve it under the mode policy: world, group, or owner")
+	fAllow     = flag.String("allow", "", "comma-separated globs of paths to serve under the glob policy")
+	fDeny      = flag.String("deny", "", "comma-separated globs of paths never to serve under the glob policy")
+
+	fHtpasswd     = flag.String("htpasswd", "", "htpasswd file of users who may log in, with bcrypt passwords")
+	fTokens       = flag.String("tokens", "", "file of bearer tokens, one \"<user> <token>\" per line")
+	fAuthRequired = flag.Bool("auth-required", false, "refuse requests from users who have not logged in")
+
+	fTLSCert = flag.String("tls-cert", "", "certificate file with which to serve HTTPS")
+	fTLSKey  = flag.String("tls-key", "", "private key file of the certificate")
+	fTLSAuto = flag.Bool("tls-auto", false, "serve HTTPS, generating a self-signed certificate if there is none")
+
+	fShutdownTimeout = flag.Duration("shutdown-timeout", 30*time.Second, "maximum time to wait for requests to finish when stopping")
+
+	fNative     = flag.Bool("native", true, "read git objects directly where possible, rather than running git")
+	fCacheSize  = flag.Int("cache-size", 64, "megabytes of query results to cache, or 0 to disable")
+	fGitTimeout = flag.Duration("git-timeout", time.Minute, "maximum time for each git command, or 0 for no limit")
+	fMaxGit     = flag.Int("max-git", runtime.NumCPU(), "maximum git processes at once, or 0 for no limit")
+	fMaxGitRepo = flag.Int("max-git-repo", 2, "maximum git processes at once in each repository, not counting clones, or 0 for no limit")
+	fGitQueue   = flag.Duration("git-queue", 10*time.Second, "maximum time to wait for a git process to finish when at the limit")
+
 	fShowVersion  = flag.Bool("version", false, "print major version and exit")
 	fShowFVersion = flag.Bool("version-full", false, "print full version and exit")
 	fShowBind     = flag.Bool("show-bind", false, "print default bind interface and exit")
@@ -65,26 +89,89 @@ func main() {
 		return
 	}
 
-	l.Println("Verision:", Version+minversion)
+	// "grove config check [file]" validates a configuration file,
+	// rather than starting the server.
+	if flag.Arg(0) == "config" {
+		if flag.Arg(1) != "check" || flag.NArg() > 3 {
+			fmt.Fprintf(os.Stderr, usage, os.Args[0], os.Args[0])
+			os.Exit(2)
+		}
+		file := *fConfig
+		if flag.NArg() == 3 {
+			file = flag.Arg(2)
+		}
+		os.Exit(CheckConfig(file))
+	}
 
-	var repodir string
-	if flag.NArg() > 0 {
-		repodir = path.Clean(flag.Arg(0))
+	// Flags given on the command line take precedence over the
+	// configuration file.
+	config, err := LoadConfig(*fConfig)
+	if err != nil {
+		l.Fatalln("Error reading configuration:", err)
+	}
+	if err = Configure(config, false); err != nil {
+		l.Fatalln("Error in configuration:", err)
+	}
 
-		if !path.IsAbs(repodir) {
-			wd, err := os.Getwd()
-			if err != nil {
-				l.Fatalln("Error getting working directory:", err)
+	l.Println("Verision:", Version+minversion)
+	if err := readGitVersion(); err != nil {
+		l.Fatalln("Error running git:", err)
+	}
+	if !pushSupported() {
+		for _, c := range config.Repos {
+			if c.Push {
+				l.Printf("Pushes are disabled: git %d.%d.%d is older "+
+					"than 2.31\n", gitVersion[0], gitVersion[1], gitVersion[2])
+				break
 			}
-			repodir = path.Join(wd, repodir)
 		}
-	} else {
-		wd, err := os.Getwd()
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		l.Fatalln("Error getting working directory:", err)
+	}
+	dirs := flag.Args()
+	if len(dirs) == 0 {
+		dirs = config.Roots
+	}
+	if len(dirs) == 0 {
+		dirs = []string{wd}
+	}
+	names := make(map[string]bool)
+	for i, dir := range dirs {
+		dir = path.Clean(dir)
+		if !path.IsAbs(dir) {
+			dir = path.Join(wd, dir)
+		}
+		dirs[i] = dir
+
+		// Several directories are served under their base names,
+		// so those must be distinct.
+		if names[path.Base(dir)] && len(dirs) > 1 {
+			l.Fatalln("More than one directory is named", path.Base(dir))
+		}
+		names[path.Base(dir)] = true
+	}
+
+	queryCache = newCache(*fCacheSize << 20)
+	gitLimiter = newLimiter(*fMaxGit, *fMaxGitRepo, *fGitQueue)
+
+	certFile, keyFile, err := TLSFiles()
+	if err != nil {
+		l.Fatalln("Error setting up TLS:", err)
+	}
+	if len(certFile) != 0 {
+		// The fingerprint is shown so that peers can check that they
+		// have the right certificate before trusting it.
+		fingerprint, err := certFingerprint(certFile)
 		if err != nil {
-			l.Fatalln("Error getting working directory:", err)
+			l.Fatalln("Error reading certificate:", err)
 		}
-		repodir = wd
+		l.Println("Certificate:", certFile)
+		l.Println("SHA-256 fingerprint:", fingerprint)
+		l.Println("Peers can trust it with: git config http.sslCAInfo <copy of certificate>")
 	}
 
-	Serve(repodir)
+	Serve(dirs, certFile, keyFile)
 }
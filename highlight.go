@@ -0,0 +1,364 @@
+package main
+
+// Copyright ⓒ 2013 Alexander Bauer and Luke Evers (see LICENSE.md)
+
+import (
+	"html"
+	"path"
+	"strings"
+)
+
+// language describes just enough of a programming language's syntax
+// to highlight keywords, literals, strings, numbers, and comments. The
+// classes produced are those styled by the "pre" rules in
+// res/style.css.
+type language struct {
+	Name          string
+	Keywords      []string
+	Literals      []string
+	LineComments  []string    // Prefixes which begin a comment
+	BlockComments [][2]string // Start and end of multi-line comments
+	BlockStrings  [][2]string // Start and end of multi-line strings
+	Quotes        string      // Characters which delimit strings
+	RawQuotes     string      // Quotes in which \ is not an escape
+}
+
+var languages = map[string]*language{
+	"go": {
+		Name: "go",
+		Keywords: []string{"break", "case", "chan", "const", "continue",
+			"default", "defer", "else", "fallthrough", "for", "func",
+			"go", "goto", "if", "import", "interface", "map", "package",
+			"range", "return", "select", "struct", "switch", "type",
+			"var"},
+		Literals:      []string{"true", "false", "nil", "iota"},
+		LineComments:  []string{"//"},
+		BlockComments: [][2]string{{"/*", "*/"}},
+		BlockStrings:  [][2]string{{"`", "`"}},
+		Quotes:        "\"'",
+	},
+	"c": {
+		Name: "c",
+		Keywords: []string{"auto", "break", "case", "char", "const",
+			"continue", "default", "do", "double", "else", "enum",
+			"extern", "float", "for", "goto", "if", "inline", "int",
+			"long", "register", "return", "short", "signed", "sizeof",
+			"static", "struct", "switch", "typedef", "union",
+			"unsigned", "void", "volatile", "while", "class",
+			"namespace", "template", "typename", "public", "private",
+			"protected", "virtual", "new", "delete", "using", "try",
+			"catch", "throw", "bool"},
+		Literals:      []string{"true", "false", "NULL", "nullptr", "this"},
+		LineComments:  []string{"//"},
+		BlockComments: [][2]string{{"/*", "*/"}},
+		Quotes:        "\"'",
+	},
+	"java": {
+		Name: "java",
+		Keywords: []string{"abstract", "boolean", "break", "byte", "case",
+			"catch", "char", "class", "const", "continue", "default",
+			"do", "double", "else", "enum", "extends", "final",
+			"finally", "float", "for", "if", "implements", "import",
+			"instanceof", "int", "interface", "long", "native", "new",
+			"package", "private", "protected", "public", "return",
+			"short", "static", "super", "switch", "synchronized",
+			"throw", "throws", "try", "void", "volatile", "while"},
+		Literals:      []string{"true", "false", "null", "this"},
+		LineComments:  []string{"//"},
+		BlockComments: [][2]string{{"/*", "*/"}},
+		Quotes:        "\"'",
+	},
+	"javascript": {
+		Name: "javascript",
+		Keywords: []string{"break", "case", "catch", "class", "const",
+			"continue", "debugger", "default", "delete", "do", "else",
+			"export", "extends", "finally", "for", "function", "if",
+			"import", "in", "instanceof", "let", "new", "return",
+			"switch", "throw", "try", "typeof", "var", "void", "while",
+			"with", "yield", "async", "await"},
+		Literals:      []string{"true", "false", "null", "undefined", "this"},
+		LineComments:  []string{"//"},
+		BlockComments: [][2]string{{"/*", "*/"}},
+		BlockStrings:  [][2]string{{"`", "`"}},
+		Quotes:        "\"'",
+	},
+	"python": {
+		Name: "python",
+		Keywords: []string{"and", "as", "assert", "break", "class",
+			"continue", "def", "del", "elif", "else", "except", "exec",
+			"finally", "for", "from", "global", "if", "import", "in",
+			"is", "lambda", "nonlocal", "not", "or", "pass", "print",
+			"raise", "return", "try", "while", "with", "yield"},
+		Literals:     []string{"True", "False", "None", "self"},
+		LineComments: []string{"#"},
+		BlockStrings: [][2]string{{"\"\"\"", "\"\"\""}, {"'''", "'''"}},
+		Quotes:       "\"'",
+	},
+	"ruby": {
+		Name: "ruby",
+		Keywords: []string{"alias", "and", "begin", "break", "case",
+			"class", "def", "do", "else", "elsif", "end",
+			"ensure", "for", "if", "in", "module", "next", "not", "or",
+			"redo", "rescue", "retry", "return", "super", "then",
+			"undef", "unless", "until", "when", "while", "yield",
+			"require"},
+		Literals:      []string{"true", "false", "nil", "self"},
+		LineComments:  []string{"#"},
+		BlockComments: [][2]string{{"=begin", "=end"}},
+		Quotes:        "\"'",
+		RawQuotes:     "'",
+	},
+	"rust": {
+		Name: "rust",
+		Keywords: []string{"as", "break", "const", "continue", "crate",
+			"else", "enum", "extern", "fn", "for", "if", "impl", "in",
+			"let", "loop", "match", "mod", "move", "mut", "pub", "ref",
+			"return", "static", "struct", "trait", "type", "unsafe",
+			"use", "where", "while"},
+		Literals:      []string{"true", "false", "self", "Self", "None", "Some"},
+		LineComments:  []string{"//"},
+		BlockComments: [][2]string{{"/*", "*/"}},
+		Quotes:        "\"",
+	},
+	"sh": {
+		Name: "sh",
+		Keywords: []string{"case", "do", "done", "elif", "else", "esac",
+			"export", "fi", "for", "function", "if", "in", "local",
+			"return", "select", "then", "until", "while", "echo",
+			"exit", "set", "shift", "source"},
+		Literals:     []string{"true", "false"},
+		LineComments: []string{"#"},
+		Quotes:       "\"'",
+		RawQuotes:    "'",
+	},
+	"css": {
+		Name:          "css",
+		BlockComments: [][2]string{{"/*", "*/"}},
+		Quotes:        "\"'",
+	},
+}
+
+// extensions maps file extensions to the languages above.
+var extensions = map[string]string{
+	".go":   "go",
+	".c":    "c",
+	".h":    "c",
+	".cc":   "c",
+	".cpp":  "c",
+	".cxx":  "c",
+	".hpp":  "c",
+	".java": "java",
+	".js":   "javascript",
+	".json": "javascript",
+	".py":   "python",
+	".rb":   "ruby",
+	".rs":   "rust",
+	".sh":   "sh",
+	".bash": "sh",
+	".css":  "css",
+}
+
+// interpreters maps the programs named in shebang lines to the
+// languages above.
+var interpreters = map[string]string{
+	"sh":      "sh",
+	"bash":    "sh",
+	"zsh":     "sh",
+	"python":  "python",
+	"python2": "python",
+	"python3": "python",
+	"ruby":    "ruby",
+	"node":    "javascript",
+}
+
+// detectLanguage determines the language of a file, first from its
+// extension, and then from its shebang line, if it has one. If the
+// language is unknown, it returns nil.
+func detectLanguage(file string, contents []byte) *language {
+	if name, ok := extensions[strings.ToLower(path.Ext(file))]; ok {
+		return languages[name]
+	}
+	if !strings.HasPrefix(string(contents), "#!") {
+		return nil
+	}
+	shebang := strings.SplitN(string(contents), "\n", 2)[0]
+	fields := strings.Fields(strings.TrimPrefix(shebang, "#!"))
+	if len(fields) == 0 {
+		return nil
+	}
+	program := path.Base(fields[0])
+	if program == "env" && len(fields) > 1 {
+		program = fields[1]
+	}
+	return languages[interpreters[program]]
+}
+
+// highlight escapes the source and marks it up with <span> elements.
+// It returns the HTML for each line separately, so that each line can
+// be given its own anchor; tokens which span several lines, such as
+// block comments, are split so that every line is well formed. If
+// lang is nil, the lines are only escaped.
+func highlight(lang *language, src string) (lines []string) {
+	var line []string
+	emit := func(class, text string) {
+		for i, part := range strings.Split(text, "\n") {
+			if i > 0 {
+				lines = append(lines, strings.Join(line, ""))
+				line = line[:0]
+			}
+			if len(part) == 0 {
+				continue
+			}
+			if len(class) == 0 {
+				line = append(line, html.EscapeString(part))
+			} else {
+				line = append(line, "<span class=\""+class+"\">"+
+					html.EscapeString(part)+"</span>")
+			}
+		}
+	}
+
+	if lang == nil {
+		emit("", src)
+	} else {
+		lang.tokenize(src, emit)
+	}
+	return append(lines, strings.Join(line, ""))
+}
+
+// tokenize splits src into tokens, and passes each one to emit, along
+// with its class. Everything in src is passed exactly once, in order.
+func (lang *language) tokenize(src string, emit func(class, text string)) {
+	i := 0
+	plain := 0 // Start of the current run of unclassified text
+	flush := func() {
+		if plain < i {
+			emit("", src[plain:i])
+		}
+	}
+	token := func(class string, end int) {
+		flush()
+		emit(class, src[i:end])
+		i = end
+		plain = i
+	}
+
+	for i < len(src) {
+		rest := src[i:]
+		if end := lang.delimited(rest, lang.BlockComments); end > 0 {
+			token("comment", i+end)
+			continue
+		}
+		if end := lang.delimited(rest, lang.BlockStrings); end > 0 {
+			token("string", i+end)
+			continue
+		}
+		if lang.isLineComment(src, i) {
+			end := strings.IndexByte(rest, '\n')
+			if end < 0 {
+				end = len(rest)
+			}
+			token("comment", i+end)
+			continue
+		}
+
+		c := src[i]
+		switch {
+		case strings.IndexByte(lang.Quotes, c) >= 0:
+			token("string", i+lang.quoted(rest))
+		case isDigit(c) && (i == 0 || !isWord(src[i-1])):
+			end := 1
+			for end < len(rest) && (isWord(rest[end]) || rest[end] == '.') {
+				end++
+			}
+			token("number", i+end)
+		case isWord(c) && (i == 0 || !isWord(src[i-1])):
+			end := 1
+			for end < len(rest) && isWord(rest[end]) {
+				end++
+			}
+			switch word := rest[:end]; {
+			case contains(lang.Keywords, word):
+				token("keyword", i+end)
+			case contains(lang.Literals, word):
+				token("literal", i+end)
+			default:
+				i += end
+			}
+		default:
+			i++
+		}
+	}
+	flush()
+}
+
+// delimited checks whether s begins with one of the given pairs of
+// delimiters, and if so, returns the length of the delimited text,
+// including the delimiters. If the end delimiter is missing, the text
+// runs to the end of s.
+func (lang *language) delimited(s string, pairs [][2]string) int {
+	for _, pair := range pairs {
+		if !strings.HasPrefix(s, pair[0]) {
+			continue
+		}
+		end := strings.Index(s[len(pair[0]):], pair[1])
+		if end < 0 {
+			return len(s)
+		}
+		return len(pair[0]) + end + len(pair[1])
+	}
+	return 0
+}
+
+// isLineComment checks whether a line comment begins at src[i]. In
+// languages where "#" begins a comment, it must not be in the middle
+// of a word, as in shell's "$#".
+func (lang *language) isLineComment(src string, i int) bool {
+	for _, prefix := range lang.LineComments {
+		if !strings.HasPrefix(src[i:], prefix) {
+			continue
+		}
+		if prefix == "#" && i > 0 && (isWord(src[i-1]) || src[i-1] == '$') {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// quoted returns the length of the string literal at the start of s,
+// including its quotes. Strings end at the closing quote or at the end
+// of the line, whichever comes first.
+func (lang *language) quoted(s string) int {
+	quote := s[0]
+	raw := strings.IndexByte(lang.RawQuotes, quote) >= 0
+	for i := 1; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && !raw:
+			i++
+		case s[i] == quote:
+			return i + 1
+		case s[i] == '\n':
+			return i
+		}
+	}
+	return len(s)
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+func isWord(c byte) bool {
+	return isDigit(c) || c == '_' || 'a' <= c && c <= 'z' ||
+		'A' <= c && c <= 'Z' || c >= 0x80
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,99 @@
+package main
+
+// Copyright ⓒ 2013 Alexander Bauer and Luke Evers (see LICENSE.md)
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		lang string
+		src  string
+		want []string // Pairs of class and text
+	}{
+		{"go", "func f() {}", []string{
+			"keyword", "func", "", " f() {}"}},
+		{"go", "x := nil // y", []string{
+			"", "x := ", "literal", "nil", "", " ", "comment", "// y"}},
+		{"go", "a /* b\nc */ d", []string{
+			"", "a ", "comment", "/* b\nc */", "", " d"}},
+		{"go", "s := \"a\\\"b\" + `c\nd`", []string{
+			"", "s := ", "string", "\"a\\\"b\"", "", " + ",
+			"string", "`c\nd`"}},
+		{"go", "x1 := 1.5e3", []string{
+			"", "x1 := ", "number", "1.5e3"}},
+		{"go", "\"open\nfor", []string{
+			"string", "\"open", "", "\n", "keyword", "for"}},
+		{"go", "/* open", []string{"comment", "/* open"}},
+		{"sh", "echo $# '\\' # done", []string{
+			"keyword", "echo", "", " $# ", "string", "'\\'", "", " ",
+			"comment", "# done"}},
+		{"go", "fortune iffy", []string{"", "fortune iffy"}},
+	}
+	for _, test := range tests {
+		var got []string
+		languages[test.lang].tokenize(test.src, func(class, text string) {
+			got = append(got, class, text)
+		})
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("tokenize(%s, %q) = %q, want %q", test.lang,
+				test.src, got, test.want)
+		}
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	tests := []struct {
+		lang string // Empty for no language
+		src  string
+		want []string
+	}{
+		{"", "a < b\nc", []string{"a &lt; b", "c"}},
+		{"", "", []string{""}},
+		{"go", "if x {\n}\n", []string{
+			`<span class="keyword">if</span> x {`, "}", ""}},
+		{"go", "/* a\n\nb */", []string{
+			`<span class="comment">/* a</span>`, "",
+			`<span class="comment">b */</span>`}},
+		{"go", `"<&>"`, []string{
+			`<span class="string">&#34;&lt;&amp;&gt;&#34;</span>`}},
+	}
+	for _, test := range tests {
+		got := highlight(languages[test.lang], test.src)
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("highlight(%q, %q) = %q, want %q", test.lang,
+				test.src, got, test.want)
+		}
+	}
+}
+
+func TestDetectLanguage(t *testing.T) {
+	tests := []struct {
+		file     string
+		contents string
+		want     string // Empty for none
+	}{
+		{"main.go", "", "go"},
+		{"README.MD", "", ""},
+		{"Lib.PY", "", "python"},
+		{"run", "#!/bin/sh\necho", "sh"},
+		{"run", "#!/usr/bin/env python3\n", "python"},
+		{"run", "#!/usr/bin/env\n", ""},
+		{"run", "#!\n", ""},
+		{"notes", "plain text", ""},
+	}
+	for _, test := range tests {
+		lang := detectLanguage(test.file, []byte(test.contents))
+		var got string
+		if lang != nil {
+			got = lang.Name
+		}
+		if got != test.want {
+			t.Errorf("detectLanguage(%q, %q) = %q, want %q", test.file,
+				strings.SplitN(test.contents, "\n", 2)[0], got, test.want)
+		}
+	}
+}
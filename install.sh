@@ -13,14 +13,17 @@ fi
 if [ -z $INSTALLDIR ]; then
 	INSTALLDIR=/usr/bin
 fi
-if [ -z $STARTUPSCRIPT ]; then
-	STARTUPSCRIPT=grove.sh
+if [ -z $MANDIR ]; then
+	MANDIR=/usr/local/share/man/man1
 fi
-if [ -z $STARTUPSCRIPTLOC ]; then
-	if [ ! -e "/etc/init.d" ]; then
-		NOINITD=TRUE
+if [ -z $UNITS ]; then
+	UNITS="grove.service grove.socket"
+fi
+if [ -z $UNITDIR ]; then
+	if [ ! -e "/usr/lib/systemd" ]; then
+		NOSYSTEMD=TRUE
 	else
-		STARTUPSCRIPTLOC=/etc/init.d/grove
+		UNITDIR=/usr/lib/systemd/user
 	fi
 fi
 
@@ -32,6 +35,19 @@ if [ "$1" = "--build" ] || [ "$1" = "-b" ]; then
 		echo
 	fi
 	
+	# Grove needs Go 1.20 or newer, and has no go.mod, so it is built
+	# in GOPATH mode, with its dependencies retrieved by 'go get'.
+	GOVERSION=$(go env GOVERSION | sed 's/^go//')
+	case "$GOVERSION" in
+	"" | 1.[0-9] | 1.[0-9].* | 1.1[0-9] | 1.1[0-9].* | 1.1[0-9][a-z]*)
+		echo "Go $GOVERSION is too old to build $GROVE; 1.20 or newer is needed."
+		exit 1
+		;;
+	esac
+	if [ -z $GO111MODULE ]; then
+		export GO111MODULE=off
+	fi
+
 	echo "Building $GROVE..."
 	$COMPILER
 	
@@ -51,18 +67,24 @@ mkdir -p -m 755 $RESDIR
 echo "Copying resources to $RESDIR"
 cp -r res/* $RESDIR/
 
-if [ "$NOINITD" != TRUE ]; then
-	echo "Copying the $STARTUPSCRIPT startup script to $STARTUPSCRIPTLOC"
-	cp $STARTUPSCRIPT $STARTUPSCRIPTLOC
-	chmod +x $STARTUPSCRIPTLOC
+echo "Copying the manual page to $MANDIR"
+mkdir -p -m 755 $MANDIR
+cp docs/grove.1 $MANDIR/
+chmod 644 $MANDIR/grove.1
+
+if [ "$NOSYSTEMD" != TRUE ]; then
+	echo "Copying the systemd user units to $UNITDIR"
+	mkdir -p -m 755 $UNITDIR
+	cp $UNITS $UNITDIR/
+	chmod 644 $UNITDIR/grove.*
 else
 	echo
 	echo "\033[1;31mPlease note:\033[1;0m"
-	echo "/etc/init.d doesn't exist, so you're probably not running"
-	echo "Debian or Ubuntu/Mint. As such, the startup script couldn't"
-	echo "be copied to a proper location. You may want to move grove.sh"
-	echo "to a place which is easy to access, so that you can start it"
-	echo "up easily."
+	echo "/usr/lib/systemd doesn't exist, so you're probably not running"
+	echo "systemd. As such, the units which start Grove couldn't be"
+	echo "installed. You may want to start it from your session instead,"
+	echo "with 'grove ~/dev &', and stop it with 'kill -TERM', which waits"
+	echo "for clones in progress to finish."
 	echo
 fi
 
@@ -74,9 +96,9 @@ echo
 echo "\033[1;32m### Installation finished. Version $VERSION\033[0m"
 echo
 
-if [ "$NOINITD" != TRUE ]; then
-	echo "You can invoke Grove as follows:"
-	echo "  service $(basename $STARTUPSCRIPTLOC) start"
+if [ "$NOSYSTEMD" != TRUE ]; then
+	echo "You can start Grove on demand, as your user, as follows:"
+	echo "  systemctl --user enable --now grove.socket"
 	echo
 fi
 
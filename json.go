@@ -5,6 +5,8 @@ package main
 import (
 	"encoding/json"
 	"net/http"
+	"strconv"
+	"strings"
 )
 
 type Summary struct {
@@ -14,11 +16,31 @@ type Summary struct {
 	Status        int       // HTTP status
 }
 
+type FileContents struct {
+	Path     string   // Path of the file within the repository
+	Ref      string   // Ref at which the file was retrieved
+	Language string   // Language used to highlight the file, if any
+	Lines    []string // Syntax-highlighted HTML for each line
+	Status   int      // HTTP status
+}
+
+// isJSONRequest checks whether a request is for the JSON interface,
+// with the "j" form value set to "true".
+func isJSONRequest(req *http.Request) bool {
+	return strings.ToLower(req.FormValue("j")) == "true"
+}
+
 func (g *git) ShowJSON(ref string, maxCommits int) (payload string, status int) {
+	commits, err := g.Commits(ref, maxCommits)
+	if err != nil {
+		status = gitStatus(err)
+		return "{\"Status\":" + strconv.Itoa(status) + "}", status
+	}
 	summary := &Summary{
 		Owner:         gitVarUser(),
 		CurrentCommit: g.SHA(ref),
-		Commits:       g.Commits(ref, maxCommits),
+		Commits:       commits,
+		Status:        http.StatusOK,
 	}
 	b, err := json.Marshal(summary)
 	if err != nil {
@@ -26,3 +48,26 @@ func (g *git) ShowJSON(ref string, maxCommits int) (payload string, status int)
 	}
 	return string(b), http.StatusOK
 }
+
+func (g *git) ShowFileJSON(ref, file string) (payload string, status int) {
+	contents, err := g.GetFile(ref, file)
+	if err != nil {
+		status = gitStatus(err)
+		return "{\"Status\":" + strconv.Itoa(status) + "}", status
+	}
+	lang := detectLanguage(file, contents)
+	fc := &FileContents{
+		Path:   file,
+		Ref:    ref,
+		Lines:  highlight(lang, string(contents)),
+		Status: http.StatusOK,
+	}
+	if lang != nil {
+		fc.Language = lang.Name
+	}
+	b, err := json.Marshal(fc)
+	if err != nil {
+		return "{\"Status\":500}", http.StatusInternalServerError
+	}
+	return string(b), http.StatusOK
+}
@@ -0,0 +1,178 @@
+package main
+
+// Copyright ⓒ 2013 Alexander Bauer and Luke Evers (see LICENSE.md)
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrBusy is given when too many git processes are already running,
+// and no slot became free while the request was queued.
+var ErrBusy = errors.New("too many git processes running")
+
+// gitLimiter bounds the number of git processes, including
+// git-http-backend, which run at once, both in total and for each
+// repository, so that heavy use of one repository cannot starve the
+// rest, or the machine grove is running on. Transfers, such as clones,
+// count only towards the total, so that they never keep the owner
+// from browsing a repository.
+var gitLimiter *limiter
+
+type limiter struct {
+	global  chan struct{}         // Slots shared by every repository
+	perRepo int                   // Number of slots for each repository
+	wait    time.Duration         // Longest time to queue for a slot
+	lock    sync.Mutex            // Guards repos
+	repos   map[string]*repoSlots // Slots of repositories in use
+}
+
+type repoSlots struct {
+	slots chan struct{} // Slots for this repository
+	users int           // Requests holding or queued for a slot
+}
+
+// newLimiter creates a limiter which allows global processes at once
+// in total, and perRepo processes at once in any single repository.
+// Either may be zero for no limit, and repositories may be given their
+// own limit in the configuration. Requests are queued for up to wait
+// for a slot to become free.
+func newLimiter(global, perRepo int, wait time.Duration) *limiter {
+	lim := &limiter{
+		perRepo: perRepo,
+		wait:    wait,
+		repos:   make(map[string]*repoSlots),
+	}
+	if global > 0 {
+		lim.global = make(chan struct{}, global)
+	}
+	return lim
+}
+
+// Acquire waits for a slot to run a process in the given repository.
+// If none becomes free within the limiter's wait, it returns ErrBusy,
+// and if ctx is done first, it returns the reason. Otherwise, release
+// must be called once the process has finished.
+func (lim *limiter) Acquire(ctx context.Context, repository string) (release func(), err error) {
+	if lim == nil {
+		return func() {}, nil
+	}
+
+	repo := lim.enter(repository)
+	timer := time.NewTimer(lim.wait)
+	defer timer.Stop()
+
+	// The repository's slot is taken first, so that requests queued
+	// behind a busy repository do not hold global slots.
+	if err = take(ctx, repo.slots, timer); err != nil {
+		lim.leave(repository)
+		return nil, err
+	}
+	if err = take(ctx, lim.global, timer); err != nil {
+		give(repo.slots)
+		lim.leave(repository)
+		return nil, err
+	}
+	return func() {
+		give(lim.global)
+		give(repo.slots)
+		lim.leave(repository)
+	}, nil
+}
+
+// AcquireTransfer waits for a slot to run a process which transfers a
+// repository to or from a client, such as git-http-backend or git
+// archive, as Acquire does. These last for as long as the client takes
+// to download, so they are only counted against the global limit,
+// leaving the slots of the repository for the short processes which
+// build its pages.
+func (lim *limiter) AcquireTransfer(ctx context.Context) (release func(), err error) {
+	if lim == nil {
+		return func() {}, nil
+	}
+	timer := time.NewTimer(lim.wait)
+	defer timer.Stop()
+	if err = take(ctx, lim.global, timer); err != nil {
+		return nil, err
+	}
+	return func() { give(lim.global) }, nil
+}
+
+// take claims a slot, waiting until the timer fires or ctx is done. A
+// nil channel has no limit.
+func take(ctx context.Context, slots chan struct{}, timer *time.Timer) error {
+	if slots == nil {
+		return nil
+	}
+	// Try first without waiting, so that a free slot is always taken,
+	// even if the wait is zero.
+	select {
+	case slots <- struct{}{}:
+		return nil
+	default:
+	}
+	select {
+	case slots <- struct{}{}:
+		return nil
+	case <-timer.C:
+		return ErrBusy
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// give releases a slot claimed by take.
+func give(slots chan struct{}) {
+	if slots != nil {
+		<-slots
+	}
+}
+
+// enter retrieves the slots of a repository, creating them if they
+// are not already in use.
+func (lim *limiter) enter(repository string) *repoSlots {
+	lim.lock.Lock()
+	defer lim.lock.Unlock()
+	repo, ok := lim.repos[repository]
+	if !ok {
+		repo = &repoSlots{}
+		perRepo := lim.perRepo
+		if max := repoConfig(repository).MaxGit; max != nil {
+			perRepo = *max
+		}
+		if perRepo > 0 {
+			repo.slots = make(chan struct{}, perRepo)
+		}
+		lim.repos[repository] = repo
+	}
+	repo.users++
+	return repo
+}
+
+// leave forgets the slots of a repository once nothing is using them.
+func (lim *limiter) leave(repository string) {
+	lim.lock.Lock()
+	defer lim.lock.Unlock()
+	repo := lim.repos[repository]
+	repo.users--
+	if repo.users == 0 {
+		delete(lim.repos, repository)
+	}
+}
+
+// setRetryAfter tells the client when to try again, if the status
+// shows that grove was too busy to answer.
+func setRetryAfter(w http.ResponseWriter, status int) {
+	if status != http.StatusServiceUnavailable {
+		return
+	}
+	seconds := 1
+	if gitLimiter != nil && gitLimiter.wait > time.Second {
+		seconds = int(gitLimiter.wait / time.Second)
+	}
+	w.Header().Set("Retry-After", strconv.Itoa(seconds))
+}
@@ -0,0 +1,103 @@
+package main
+
+// Copyright ⓒ 2013 Alexander Bauer and Luke Evers (see LICENSE.md)
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// unixPrefix marks a -bind address which is the path of a Unix socket,
+// rather than an interface.
+const unixPrefix = "unix:"
+
+// listenFDsStart is the first file descriptor passed by systemd, or
+// anything else which implements its socket activation protocol.
+const listenFDsStart = 3
+
+// Listen opens the sockets on which to serve. If the process was
+// started with sockets already open, as systemd does for socket
+// activation, those are used. Otherwise, if -bind is "unix:<path>",
+// it listens on a Unix socket at that path, and if not, on -bind and
+// -port.
+func Listen() ([]net.Listener, error) {
+	listeners, err := inheritedListeners()
+	if err != nil || len(listeners) != 0 {
+		return listeners, err
+	}
+	if socket, ok := strings.CutPrefix(*fBind, unixPrefix); ok {
+		ln, err := listenUnix(socket)
+		if err != nil {
+			return nil, err
+		}
+		return []net.Listener{ln}, nil
+	}
+	ln, err := net.Listen("tcp", *fBind+":"+*fPort)
+	if err != nil {
+		return nil, err
+	}
+	return []net.Listener{ln}, nil
+}
+
+// inheritedListeners retrieves the sockets passed to the process with
+// LISTEN_FDS, if they were meant for it, according to LISTEN_PID. The
+// variables are then removed, so that they are not passed on to git.
+func inheritedListeners() (listeners []net.Listener, err error) {
+	pid, _ := strconv.Atoi(os.Getenv("LISTEN_PID"))
+	fds := os.Getenv("LISTEN_FDS")
+	os.Unsetenv("LISTEN_PID")
+	os.Unsetenv("LISTEN_FDS")
+	os.Unsetenv("LISTEN_FDNAMES")
+	if len(fds) == 0 || pid != os.Getpid() {
+		return nil, nil
+	}
+	n, err := strconv.Atoi(fds)
+	if err != nil || n < 1 {
+		return nil, fmt.Errorf("invalid LISTEN_FDS %q", fds)
+	}
+	for fd := listenFDsStart; fd < listenFDsStart+n; fd++ {
+		f := os.NewFile(uintptr(fd), "LISTEN_FD_"+strconv.Itoa(fd))
+		ln, err := net.FileListener(f)
+		f.Close()
+		if err != nil {
+			for _, ln := range listeners {
+				ln.Close()
+			}
+			return nil, fmt.Errorf("inherited socket %d: %s", fd, err)
+		}
+		listeners = append(listeners, ln)
+	}
+	return listeners, nil
+}
+
+// listenUnix listens on a Unix socket at the given path. If a socket
+// is left there from a previous run, but nothing is listening on it
+// any longer, it is replaced. The socket is removed when the listener
+// is closed.
+func listenUnix(socket string) (net.Listener, error) {
+	if info, err := os.Lstat(socket); err == nil {
+		if info.Mode()&os.ModeSocket == 0 {
+			return nil, errors.New(socket + " exists and is not a socket")
+		}
+		if conn, err := net.Dial("unix", socket); err == nil {
+			conn.Close()
+			return nil, errors.New(socket + " is already in use")
+		}
+		if err = os.Remove(socket); err != nil {
+			return nil, err
+		}
+	}
+	return net.Listen("unix", socket)
+}
+
+// isUnixRequest checks whether a request was received on a Unix
+// socket, which can only be reached by a proxy on the same host.
+func isUnixRequest(req *http.Request) bool {
+	addr, ok := req.Context().Value(http.LocalAddrContextKey).(net.Addr)
+	return ok && addr.Network() == "unix"
+}
@@ -0,0 +1,1081 @@
+package main
+
+// Copyright ⓒ 2013 Alexander Bauer and Luke Evers (see LICENSE.md)
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"container/heap"
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The native reader parses the object database of a repository
+// directly, so that the common queries made by the web interface do
+// not need to start a git process each. It understands loose objects,
+// version 2 pack indexes, packfiles (including deltas), loose refs,
+// and packed refs. Anything else, such as revision expressions like
+// HEAD~2, produces errNative, and the caller falls back to running
+// git.
+
+var (
+	errNative   = errors.New("not supported by the native reader")
+	errNotFound = errors.New("object not found")
+)
+
+const (
+	// nativeCacheSize is the number of bytes of recently read objects
+	// which each repository keeps, mostly to speed up deltas which
+	// share a base.
+	nativeCacheSize = 32 << 20
+)
+
+type object struct {
+	Type string // "commit", "tree", "blob", or "tag"
+	Data []byte // Contents of the object
+}
+
+type repository struct {
+	GitDir    string          // Path of the .git directory
+	CommonDir string          // Path of the directory shared by worktrees
+	packs     []*pack         // Packfiles in the object database
+	ctx       context.Context // Context of the request, if any
+
+	cache     map[string]*object // Recently read objects
+	cacheSize int                // Total size of cached objects
+	refs      map[string]string  // Full ref names to SHAs, once read
+}
+
+type pack struct {
+	Path    string   // Path of the .pack file
+	fanout  []uint32 // Number of objects with first byte <= each index
+	shas    []byte   // Sorted 20-byte SHAs of each object
+	offsets []byte   // 4-byte offset of each object in the pack
+	large   []byte   // 8-byte offsets for packs larger than 2GB
+	file    *os.File // The open .pack file
+}
+
+var (
+	// packs holds every pack index which has been read, by path.
+	// Packs are never modified once written, so they are only
+	// discarded when they disappear from the object database. Even
+	// then, requests in progress may still be reading them, so their
+	// files are left open until nothing refers to them, when they
+	// are closed by the garbage collector.
+	packs     = make(map[string]*pack)
+	packsLock sync.Mutex
+)
+
+// openRepository prepares the native reader for the repository at the
+// given path. It fails if the native reader has been disabled, or if
+// the repository does not have an ordinary .git directory. Linked
+// worktrees keep their own HEAD, but share the objects and most refs
+// of the repository named by their commondir file.
+func openRepository(p string) (r *repository, err error) {
+	if !setting(fNative) || len(p) == 0 {
+		return nil, errNative
+	}
+	gitDir := path.Join(p, ".git")
+	fi, err := os.Stat(gitDir)
+	if err != nil {
+		return nil, err
+	}
+	if !fi.IsDir() {
+		// A .git file, as used by worktrees and submodules, points
+		// to the real directory.
+		b, err := ioutil.ReadFile(gitDir)
+		if err != nil || !bytes.HasPrefix(b, []byte("gitdir: ")) {
+			return nil, errNative
+		}
+		gitDir = strings.TrimSpace(string(b[len("gitdir: "):]))
+		if !path.IsAbs(gitDir) {
+			gitDir = path.Join(p, gitDir)
+		}
+	}
+
+	commonDir := gitDir
+	if b, err := ioutil.ReadFile(path.Join(gitDir, "commondir")); err == nil {
+		commonDir = strings.TrimSpace(string(b))
+		if !path.IsAbs(commonDir) {
+			commonDir = path.Join(gitDir, commonDir)
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	r = &repository{
+		GitDir:    gitDir,
+		CommonDir: commonDir,
+		ctx:       context.Background(),
+		cache:     make(map[string]*object),
+	}
+	r.packs, err = openPacks(path.Join(commonDir, "objects", "pack"))
+	return r, err
+}
+
+// openPacks retrieves every pack in the given directory, reading the
+// index of any which have not been seen before. Packs which have been
+// seen before, but are no longer present, are forgotten.
+func openPacks(dir string) (found []*pack, err error) {
+	idxs, err := filepath.Glob(path.Join(dir, "*.idx"))
+	if err != nil {
+		return nil, err
+	}
+
+	packsLock.Lock()
+	defer packsLock.Unlock()
+
+	present := make(map[string]bool, len(idxs))
+	for _, idx := range idxs {
+		present[idx] = true
+		p, ok := packs[idx]
+		if !ok {
+			p, err = openPack(idx)
+			if err != nil {
+				// The pack may be in the middle of being written, or
+				// be in an unsupported format. Its objects will not
+				// be found, and git will be used instead.
+				continue
+			}
+			packs[idx] = p
+		}
+		found = append(found, p)
+	}
+	for idx := range packs {
+		if strings.HasPrefix(idx, dir+"/") && !present[idx] {
+			delete(packs, idx)
+		}
+	}
+	return found, nil
+}
+
+// openPack reads a version 2 pack index, and opens the pack which it
+// describes.
+func openPack(idx string) (p *pack, err error) {
+	b, err := ioutil.ReadFile(idx)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) < 8+256*4 || !bytes.Equal(b[:4], []byte("\377tOc")) ||
+		binary.BigEndian.Uint32(b[4:8]) != 2 {
+		return nil, errNative
+	}
+
+	p = &pack{
+		Path:   strings.TrimSuffix(idx, ".idx") + ".pack",
+		fanout: make([]uint32, 256),
+	}
+	for i := range p.fanout {
+		p.fanout[i] = binary.BigEndian.Uint32(b[8+i*4:])
+	}
+	n := int(p.fanout[255])
+	start := 8 + 256*4
+	if len(b) < start+n*(20+4+4) {
+		return nil, errNative
+	}
+	p.shas = b[start : start+n*20]
+	start += n * 20
+	start += n * 4 // Skip the CRCs.
+	p.offsets = b[start : start+n*4]
+	start += n * 4
+	p.large = b[start:]
+
+	p.file, err = os.Open(p.Path)
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// find retrieves the offset of the given object in the pack.
+func (p *pack) find(sha []byte) (offset int64, ok bool) {
+	lo := 0
+	if sha[0] > 0 {
+		lo = int(p.fanout[sha[0]-1])
+	}
+	hi := int(p.fanout[sha[0]])
+	i := lo + sort.Search(hi-lo, func(i int) bool {
+		return bytes.Compare(p.shas[(lo+i)*20:(lo+i+1)*20], sha) >= 0
+	})
+	if i >= hi || !bytes.Equal(p.shas[i*20:(i+1)*20], sha) {
+		return 0, false
+	}
+	return p.offset(i), true
+}
+
+// offset retrieves the offset of the ith object in the index.
+func (p *pack) offset(i int) int64 {
+	o := binary.BigEndian.Uint32(p.offsets[i*4:])
+	if o&0x80000000 == 0 {
+		return int64(o)
+	}
+	i = int(o & 0x7fffffff)
+	if len(p.large) < (i+1)*8 {
+		return -1
+	}
+	return int64(binary.BigEndian.Uint64(p.large[i*8:]))
+}
+
+// abbreviated retrieves the SHAs of every object in the pack which
+// begins with the given hexadecimal prefix.
+func (p *pack) abbreviated(prefix string) (shas []string) {
+	lo, hi := 0, int(p.fanout[255])
+	if first, err := hex.DecodeString(prefix[:2]); err == nil {
+		if first[0] > 0 {
+			lo = int(p.fanout[first[0]-1])
+		}
+		hi = int(p.fanout[first[0]])
+	}
+	for i := lo; i < hi; i++ {
+		sha := hex.EncodeToString(p.shas[i*20 : (i+1)*20])
+		if strings.HasPrefix(sha, prefix) {
+			shas = append(shas, sha)
+		}
+	}
+	return
+}
+
+// read retrieves an object from the repository, looking first for a
+// loose object, and then in each pack.
+func (r *repository) read(sha string) (o *object, err error) {
+	if o, ok := r.cache[sha]; ok {
+		return o, nil
+	}
+	raw, err := hex.DecodeString(sha)
+	if err != nil || len(raw) != 20 {
+		return nil, errNotFound
+	}
+
+	o, err = r.readLoose(sha)
+	if err != nil {
+		for _, p := range r.packs {
+			if offset, ok := p.find(raw); ok {
+				o, err = r.readPacked(p, offset)
+				break
+			}
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	r.remember(sha, o)
+	return o, nil
+}
+
+// remember adds an object to the cache, emptying it first if it has
+// grown too large.
+func (r *repository) remember(key string, o *object) {
+	if r.cacheSize+len(o.Data) > nativeCacheSize {
+		r.cache = make(map[string]*object)
+		r.cacheSize = 0
+	}
+	r.cache[key] = o
+	r.cacheSize += len(o.Data)
+}
+
+// readLoose reads an object stored in its own file.
+func (r *repository) readLoose(sha string) (o *object, err error) {
+	f, err := os.Open(path.Join(r.CommonDir, "objects", sha[:2], sha[2:]))
+	if err != nil {
+		return nil, errNotFound
+	}
+	defer f.Close()
+	z, err := zlib.NewReader(f)
+	if err != nil {
+		return nil, err
+	}
+	defer z.Close()
+	b, err := ioutil.ReadAll(z)
+	if err != nil {
+		return nil, err
+	}
+
+	// The object begins with a header of the form "<type> <size>\0".
+	nul := bytes.IndexByte(b, 0)
+	if nul < 0 {
+		return nil, errNative
+	}
+	header := strings.SplitN(string(b[:nul]), " ", 2)
+	return &object{Type: header[0], Data: b[nul+1:]}, nil
+}
+
+// packTypes are the names of the object types used in packs.
+var packTypes = map[byte]string{
+	1: "commit",
+	2: "tree",
+	3: "blob",
+	4: "tag",
+}
+
+const (
+	packOfsDelta = 6 // Delta against an object earlier in the pack
+	packRefDelta = 7 // Delta against an object given by SHA
+)
+
+// readPacked reads the object at the given offset in a pack, applying
+// deltas as necessary.
+func (r *repository) readPacked(p *pack, offset int64) (o *object, err error) {
+	key := p.Path + "@" + strconv.FormatInt(offset, 10)
+	if o, ok := r.cache[key]; ok {
+		return o, nil
+	}
+	if offset < 0 {
+		return nil, errNative
+	}
+
+	br := bufio.NewReader(io.NewSectionReader(p.file, offset, 1<<62))
+	typ, size, baseOffset, baseSHA, err := readPackHeader(br, offset)
+	if err != nil {
+		return nil, err
+	}
+	var base *object
+	switch typ {
+	case packOfsDelta:
+		base, err = r.readPacked(p, baseOffset)
+	case packRefDelta:
+		base, err = r.read(baseSHA)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	z, err := zlib.NewReader(br)
+	if err != nil {
+		return nil, err
+	}
+	defer z.Close()
+	data := make([]byte, size)
+	if _, err = io.ReadFull(z, data); err != nil {
+		return nil, err
+	}
+
+	if base == nil {
+		o = &object{Type: packTypes[typ], Data: data}
+	} else {
+		patched, err := applyDelta(base.Data, data)
+		if err != nil {
+			return nil, err
+		}
+		o = &object{Type: base.Type, Data: patched}
+	}
+	r.remember(key, o)
+	return o, nil
+}
+
+// readPackHeader reads the header of the object at the given offset in
+// a pack, which gives its type and size. For a delta, the size is that
+// of the delta, and the base is given either by its offset or its SHA.
+func readPackHeader(br *bufio.Reader, offset int64) (typ byte, size, baseOffset int64, baseSHA string, err error) {
+	// The type is in bits 4-6 of the first byte, and the size in the
+	// remaining bits, continued while the top bit is set.
+	c, err := br.ReadByte()
+	if err != nil {
+		return
+	}
+	typ = (c >> 4) & 7
+	size = int64(c & 15)
+	for shift := uint(4); c&0x80 != 0; shift += 7 {
+		if c, err = br.ReadByte(); err != nil {
+			return
+		}
+		size |= int64(c&0x7f) << shift
+	}
+
+	switch typ {
+	case packOfsDelta:
+		// The base is given as a negative offset, in a variable
+		// length encoding in which each continuation adds one.
+		if c, err = br.ReadByte(); err != nil {
+			return
+		}
+		rel := int64(c & 0x7f)
+		for c&0x80 != 0 {
+			if c, err = br.ReadByte(); err != nil {
+				return
+			}
+			rel = ((rel + 1) << 7) | int64(c&0x7f)
+		}
+		baseOffset = offset - rel
+	case packRefDelta:
+		sha := make([]byte, 20)
+		if _, err = io.ReadFull(br, sha); err != nil {
+			return
+		}
+		baseSHA = hex.EncodeToString(sha)
+	default:
+		if _, ok := packTypes[typ]; !ok {
+			err = errNative
+		}
+	}
+	return
+}
+
+// size retrieves the size of an object without reading all of it. Only
+// the header of a loose object is inflated, and for a delta in a pack,
+// only its beginning, which gives the size of the result.
+func (r *repository) size(sha string) (size int64, err error) {
+	if o, ok := r.cache[sha]; ok {
+		return int64(len(o.Data)), nil
+	}
+	raw, err := hex.DecodeString(sha)
+	if err != nil || len(raw) != 20 {
+		return 0, errNotFound
+	}
+
+	if f, err := os.Open(path.Join(r.CommonDir, "objects", sha[:2], sha[2:])); err == nil {
+		defer f.Close()
+		z, err := zlib.NewReader(f)
+		if err != nil {
+			return 0, err
+		}
+		defer z.Close()
+		header, err := bufio.NewReader(z).ReadString(0)
+		if err != nil {
+			return 0, err
+		}
+		fields := strings.Fields(strings.TrimSuffix(header, "\x00"))
+		if len(fields) != 2 {
+			return 0, errNative
+		}
+		return strconv.ParseInt(fields[1], 10, 64)
+	}
+
+	for _, p := range r.packs {
+		offset, ok := p.find(raw)
+		if !ok {
+			continue
+		}
+		if offset < 0 {
+			return 0, errNative
+		}
+		br := bufio.NewReader(io.NewSectionReader(p.file, offset, 1<<62))
+		typ, size, _, _, err := readPackHeader(br, offset)
+		if err != nil || (typ != packOfsDelta && typ != packRefDelta) {
+			return size, err
+		}
+		z, err := zlib.NewReader(br)
+		if err != nil {
+			return 0, err
+		}
+		defer z.Close()
+		zr := bufio.NewReader(z)
+		if _, err = readVarint(zr); err != nil {
+			return 0, err
+		}
+		return readVarint(zr)
+	}
+	return 0, errNotFound
+}
+
+// readVarint reads a size as encoded at the start of a delta, seven
+// bits at a time, least significant first.
+func readVarint(br io.ByteReader) (n int64, err error) {
+	for shift := uint(0); ; shift += 7 {
+		c, err := br.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		n |= int64(c&0x7f) << shift
+		if c&0x80 == 0 {
+			return n, nil
+		}
+	}
+}
+
+// applyDelta reconstructs an object from its base and a delta, which
+// is a sequence of instructions to either copy a range of the base, or
+// insert new data.
+func applyDelta(base, delta []byte) (result []byte, err error) {
+	varint := func() (n int) {
+		for shift := uint(0); len(delta) > 0; shift += 7 {
+			c := delta[0]
+			delta = delta[1:]
+			n |= int(c&0x7f) << shift
+			if c&0x80 == 0 {
+				break
+			}
+		}
+		return
+	}
+
+	if varint() != len(base) {
+		return nil, errNative
+	}
+	result = make([]byte, 0, varint())
+	for len(delta) > 0 {
+		op := delta[0]
+		delta = delta[1:]
+		switch {
+		case op&0x80 != 0:
+			// Copy. The low four bits say which bytes of the offset
+			// are present, and the next three which of the size.
+			var offset, size int
+			for i := uint(0); i < 7; i++ {
+				if op&(1<<i) == 0 {
+					continue
+				}
+				if len(delta) == 0 {
+					return nil, errNative
+				}
+				if i < 4 {
+					offset |= int(delta[0]) << (8 * i)
+				} else {
+					size |= int(delta[0]) << (8 * (i - 4))
+				}
+				delta = delta[1:]
+			}
+			if size == 0 {
+				size = 0x10000
+			}
+			if offset+size > len(base) {
+				return nil, errNative
+			}
+			result = append(result, base[offset:offset+size]...)
+		case op != 0:
+			// Insert the next op bytes.
+			if int(op) > len(delta) {
+				return nil, errNative
+			}
+			result = append(result, delta[:op]...)
+			delta = delta[op:]
+		default:
+			return nil, errNative
+		}
+	}
+	return result, nil
+}
+
+// allRefs retrieves every ref in the repository by its full name, from
+// both packed-refs and the loose refs, which take precedence.
+func (r *repository) allRefs() (refs map[string]string, err error) {
+	if r.refs != nil {
+		return r.refs, nil
+	}
+	// The packed refs are made available first, so that loose symbolic
+	// refs can point to them.
+	refs = make(map[string]string)
+	r.refs = refs
+	defer func() {
+		if err != nil {
+			r.refs = nil
+		}
+	}()
+
+	b, err := ioutil.ReadFile(path.Join(r.CommonDir, "packed-refs"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	for _, l := range strings.Split(string(b), "\n") {
+		// Comments begin with '#', and peeled tags with '^'.
+		fields := strings.Fields(l)
+		if len(fields) == 2 && len(fields[0]) == 40 {
+			refs[fields[1]] = fields[0]
+		}
+	}
+
+	// A worktree has refs of its own, such as those made by git
+	// bisect, besides those it shares.
+	dirs := []string{r.CommonDir}
+	if r.GitDir != r.CommonDir {
+		dirs = append(dirs, r.GitDir)
+	}
+	for _, dir := range dirs {
+		refsDir := path.Join(dir, "refs")
+		if _, err = os.Stat(refsDir); os.IsNotExist(err) && dir != r.CommonDir {
+			continue
+		}
+		err = filepath.Walk(refsDir, func(p string, info os.FileInfo, err error) error {
+			if err != nil || info.IsDir() {
+				return err
+			}
+			name := strings.TrimPrefix(p, dir+"/")
+			if r.refDir(name) != dir {
+				return nil
+			}
+			sha, err := r.resolveRef(name, 0)
+			switch err {
+			case nil:
+				refs[name] = sha
+			case errNotFound:
+				// Symbolic refs may point to refs which do not
+				// exist, which git also leaves out.
+			default:
+				return err
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return refs, nil
+}
+
+// refDir retrieves the directory in which the ref with the given full
+// name is stored. In a linked worktree, HEAD and a few others belong
+// to the worktree, and the rest are shared, as described in
+// gitrepository-layout(5).
+func (r *repository) refDir(name string) string {
+	if !strings.HasPrefix(name, "refs/") ||
+		strings.HasPrefix(name, "refs/bisect/") ||
+		strings.HasPrefix(name, "refs/worktree/") ||
+		strings.HasPrefix(name, "refs/rewritten/") {
+		return r.GitDir
+	}
+	return r.CommonDir
+}
+
+// resolveRef retrieves the SHA to which a ref, given by its full name
+// (such as "HEAD" or "refs/heads/master"), points, following symbolic
+// refs.
+func (r *repository) resolveRef(name string, depth int) (sha string, err error) {
+	if depth > 5 {
+		return "", errNative
+	}
+	b, err := ioutil.ReadFile(path.Join(r.refDir(name), name))
+	if err != nil {
+		// The ref may be packed.
+		if strings.HasPrefix(name, "refs/") {
+			if r.refs != nil {
+				if sha, ok := r.refs[name]; ok {
+					return sha, nil
+				}
+			} else if refs, err := r.allRefs(); err == nil {
+				if sha, ok := refs[name]; ok {
+					return sha, nil
+				}
+			}
+		}
+		return "", errNotFound
+	}
+	content := strings.TrimSpace(string(b))
+	if strings.HasPrefix(content, "ref: ") {
+		return r.resolveRef(strings.TrimPrefix(content, "ref: "), depth+1)
+	}
+	if len(content) != 40 || !isSHA(content) {
+		return "", errNative
+	}
+	return content, nil
+}
+
+// symbolicRef retrieves the full name of the ref to which a symbolic
+// ref, such as HEAD, points. If it is not symbolic, ok is false.
+func (r *repository) symbolicRef(name string) (target string, ok bool) {
+	b, err := ioutil.ReadFile(path.Join(r.refDir(name), name))
+	if err != nil {
+		return "", false
+	}
+	content := strings.TrimSpace(string(b))
+	if !strings.HasPrefix(content, "ref: ") {
+		return "", false
+	}
+	return strings.TrimPrefix(content, "ref: "), true
+}
+
+// refRules are the places in which a short ref name is looked for, in
+// order, as in git-rev-parse(1).
+var refRules = []string{
+	"%s",
+	"refs/%s",
+	"refs/tags/%s",
+	"refs/heads/%s",
+	"refs/remotes/%s",
+	"refs/remotes/%s/HEAD",
+}
+
+// resolve retrieves the full SHA of the object named by rev, which may
+// be a full or abbreviated SHA, or a ref name. More complex revisions,
+// such as those using ~, ^, or :, produce errNative.
+func (r *repository) resolve(rev string) (sha string, err error) {
+	if len(rev) == 0 || strings.HasPrefix(rev, "-") ||
+		strings.ContainsAny(rev, "~^:@ \\*?[") || strings.Contains(rev, "..") {
+		return "", errNative
+	}
+	if len(rev) == 40 && isSHA(rev) {
+		if _, err := r.read(rev); err == nil {
+			return rev, nil
+		}
+	}
+	for _, rule := range refRules {
+		name := strings.Replace(rule, "%s", rev, 1)
+		if sha, err := r.resolveRef(name, 0); err == nil {
+			return sha, nil
+		}
+	}
+	if isSHA(rev) && len(rev) < 40 {
+		return r.abbreviated(rev)
+	}
+	return "", errNotFound
+}
+
+// abbreviated finds the single object whose SHA begins with prefix.
+func (r *repository) abbreviated(prefix string) (sha string, err error) {
+	found := make(map[string]bool)
+	dir := path.Join(r.CommonDir, "objects", prefix[:2])
+	if names, err := filepath.Glob(path.Join(dir, prefix[2:]+"*")); err == nil {
+		for _, n := range names {
+			found[prefix[:2]+path.Base(n)] = true
+		}
+	}
+	for _, p := range r.packs {
+		for _, s := range p.abbreviated(prefix) {
+			found[s] = true
+		}
+	}
+	if len(found) != 1 {
+		// Ambiguous abbreviations are left to git to explain.
+		return "", errNotFound
+	}
+	for s := range found {
+		sha = s
+	}
+	return sha, nil
+}
+
+// peel follows tags until it reaches an object of another type.
+func (r *repository) peel(sha string) (o *object, peeled string, err error) {
+	for i := 0; i < 10; i++ {
+		o, err = r.read(sha)
+		if err != nil {
+			return nil, "", err
+		}
+		if o.Type != "tag" {
+			return o, sha, nil
+		}
+		// A tag begins with "object <sha>".
+		l := strings.SplitN(string(o.Data), "\n", 2)[0]
+		sha = strings.TrimPrefix(l, "object ")
+	}
+	return nil, "", errNative
+}
+
+// commit retrieves and parses the commit named by rev, peeling tags.
+func (r *repository) commit(rev string) (c *Commit, err error) {
+	sha, err := r.resolve(rev)
+	if err != nil {
+		return nil, err
+	}
+	o, sha, err := r.peel(sha)
+	if err != nil {
+		return nil, err
+	}
+	if o.Type != "commit" {
+		return nil, errNative
+	}
+	return parseCommit(sha, o.Data)
+}
+
+// parseCommit parses the raw contents of a commit object.
+func parseCommit(sha string, data []byte) (c *Commit, err error) {
+	c = &Commit{SHA: sha, Parents: []string{}}
+	headers := string(data)
+	message := ""
+	if i := strings.Index(headers, "\n\n"); i >= 0 {
+		headers, message = headers[:i], headers[i+2:]
+	}
+	for _, l := range strings.Split(headers, "\n") {
+		// Continuation lines, such as those of signatures, begin with
+		// a space, and are ignored.
+		parts := strings.SplitN(l, " ", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		switch parts[0] {
+		case "tree":
+			c.Tree = parts[1]
+		case "parent":
+			c.Parents = append(c.Parents, parts[1])
+		case "author":
+			c.Author, c.AuthorEmail, c.AuthorDate = parseIdent(parts[1])
+		case "committer":
+			c.Committer, c.CommitterEmail, c.CommitDate = parseIdent(parts[1])
+		}
+	}
+	c.Time = relativeTime(c.CommitDate)
+
+	// As with %s and %b, the subject is the first paragraph, joined
+	// into one line, and the body is the rest.
+	message = strings.TrimLeft(message, "\n")
+	subject := message
+	if i := strings.Index(message, "\n\n"); i >= 0 {
+		subject, c.Body = message[:i], strings.TrimLeft(message[i+2:], "\n")
+	}
+	c.Subject = strings.Join(strings.Fields(strings.Replace(subject, "\n", " ", -1)), " ")
+	return c, nil
+}
+
+// parseIdent parses an identity line of the form
+//
+//	Name <email> <unix time> <timezone>
+func parseIdent(ident string) (name, email string, t time.Time) {
+	lt := strings.Index(ident, "<")
+	gt := strings.LastIndex(ident, ">")
+	if lt < 0 || gt < lt {
+		return ident, "", t
+	}
+	name = strings.TrimSpace(ident[:lt])
+	email = ident[lt+1 : gt]
+
+	fields := strings.Fields(ident[gt+1:])
+	if len(fields) != 2 {
+		return
+	}
+	sec, _ := strconv.ParseInt(fields[0], 10, 64)
+	zone, _ := strconv.Atoi(fields[1])
+	offset := (zone/100)*3600 + (zone%100)*60
+	t = time.Unix(sec, 0).In(time.FixedZone("", offset))
+	return
+}
+
+// tree retrieves the entries of the directory at the given path, in
+// the tree of the commit named by rev. The top level directory is
+// given as an empty string.
+func (r *repository) tree(rev, dir string) (entries []*TreeEntry, err error) {
+	c, err := r.commit(rev)
+	if err != nil {
+		return nil, err
+	}
+	sha, typ, err := r.lookup(c.Tree, dir)
+	if err != nil {
+		return nil, err
+	}
+	if typ != "tree" {
+		return nil, errNative
+	}
+	o, err := r.read(sha)
+	if err != nil {
+		return nil, err
+	}
+	entries, err = parseTree(o.Data, cleanPath(dir))
+	if err != nil {
+		return nil, err
+	}
+
+	// Sizes are given for blobs, as by 'git ls-tree -l'.
+	for _, e := range entries {
+		if e.Type != "blob" {
+			continue
+		}
+		if e.Size, err = r.size(e.SHA); err != nil {
+			return nil, err
+		}
+	}
+	return entries, nil
+}
+
+// file retrieves the contents of the file at the given path, in the
+// tree of the commit named by rev.
+func (r *repository) file(rev, file string) (contents []byte, err error) {
+	c, err := r.commit(rev)
+	if err != nil {
+		return nil, err
+	}
+	sha, typ, err := r.lookup(c.Tree, file)
+	if err != nil {
+		return nil, err
+	}
+	if typ != "blob" {
+		// Directories and submodules cannot be read as files.
+		return nil, ErrNotFound
+	}
+	o, err := r.read(sha)
+	if err != nil {
+		return nil, err
+	}
+	return o.Data, nil
+}
+
+// lookup walks from the given tree to the object at the given path,
+// and retrieves its SHA and type.
+func (r *repository) lookup(tree, p string) (sha, typ string, err error) {
+	sha, typ = tree, "tree"
+	p = cleanPath(p)
+	if len(p) == 0 {
+		return
+	}
+	for _, name := range strings.Split(p, "/") {
+		if typ != "tree" {
+			return "", "", errNotFound
+		}
+		o, err := r.read(sha)
+		if err != nil {
+			return "", "", err
+		}
+		entries, err := parseTree(o.Data, "")
+		if err != nil {
+			return "", "", err
+		}
+		found := false
+		for _, e := range entries {
+			if e.Name == name {
+				sha, typ, found = e.SHA, e.Type, true
+				break
+			}
+		}
+		if !found {
+			return "", "", errNotFound
+		}
+	}
+	return
+}
+
+// cleanPath removes leading "./" and surrounding slashes from a path
+// within a repository, so that the top level is an empty string.
+func cleanPath(p string) string {
+	p = path.Clean("/" + p)
+	return strings.Trim(p, "/")
+}
+
+// parseTree parses the raw contents of a tree object, in which each
+// entry is "<octal mode> <name>\0<20-byte SHA>". The paths of the
+// entries are given relative to dir.
+func parseTree(data []byte, dir string) (entries []*TreeEntry, err error) {
+	for len(data) > 0 {
+		sp := bytes.IndexByte(data, ' ')
+		nul := bytes.IndexByte(data, 0)
+		if sp < 0 || nul < sp || len(data) < nul+21 {
+			return nil, errNative
+		}
+		mode := string(data[:sp])
+		name := string(data[sp+1 : nul])
+		e := &TreeEntry{
+			Name: name,
+			Path: path.Join(dir, name),
+			Type: "blob",
+			Mode: strings.Repeat("0", 6-len(mode)) + mode,
+			SHA:  hex.EncodeToString(data[nul+1 : nul+21]),
+		}
+		switch mode {
+		case "40000":
+			e.Type = "tree"
+		case "160000":
+			e.Type = "commit"
+		}
+		entries = append(entries, e)
+		data = data[nul+21:]
+	}
+	return entries, nil
+}
+
+// commitQueue orders commits by their commit dates, newest first, as
+// 'git log' does by default. Commits with the same date are taken in
+// the order in which they were added, also as git does, so that both
+// walk the history in exactly the same order.
+type commitQueue []queuedCommit
+
+type queuedCommit struct {
+	*Commit
+	n int // Number of commits added to the queue before this one
+}
+
+func (q commitQueue) Len() int { return len(q) }
+func (q commitQueue) Less(i, j int) bool {
+	if !q[i].CommitDate.Equal(q[j].CommitDate) {
+		return q[i].CommitDate.After(q[j].CommitDate)
+	}
+	return q[i].n < q[j].n
+}
+func (q commitQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *commitQueue) Push(x interface{}) { *q = append(*q, x.(queuedCommit)) }
+func (q *commitQueue) Pop() interface{} {
+	old := *q
+	c := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return c
+}
+
+// log walks the history from the given commits, newest first, and
+// calls visit for each commit until it returns false. As with git
+// itself, the walk is abandoned if it takes longer than -git-timeout,
+// or if the request is cancelled.
+func (r *repository) log(revs []string, visit func(c *Commit) bool) (err error) {
+	ctx := r.ctx
+	if timeout := setting(fGitTimeout); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	seen := make(map[string]bool)
+	q := &commitQueue{}
+	added := 0
+	push := func(c *Commit) {
+		heap.Push(q, queuedCommit{c, added})
+		added++
+	}
+	for _, rev := range revs {
+		c, err := r.commit(rev)
+		if err != nil {
+			return err
+		}
+		if !seen[c.SHA] {
+			seen[c.SHA] = true
+			push(c)
+		}
+	}
+
+	for n := 0; q.Len() > 0; n++ {
+		if n%256 == 0 && ctx.Err() != nil {
+			return newGitError(ctx, []string{"log"}, ctx.Err())
+		}
+		c := heap.Pop(q).(queuedCommit).Commit
+		if !visit(c) {
+			return nil
+		}
+		for _, parent := range c.Parents {
+			if seen[parent] {
+				continue
+			}
+			seen[parent] = true
+			o, err := r.read(parent)
+			if err != nil {
+				return err
+			}
+			p, err := parseCommit(parent, o.Data)
+			if err != nil {
+				return err
+			}
+			push(p)
+		}
+	}
+	return nil
+}
+
+// commits retrieves up to max commits reachable from rev, newest
+// first. If max is not positive, every commit is retrieved.
+func (r *repository) commits(rev string, max int) (commits []*Commit, err error) {
+	err = r.log([]string{rev}, func(c *Commit) bool {
+		commits = append(commits, c)
+		return max <= 0 || len(commits) < max
+	})
+	return commits, err
+}
+
+// refNames retrieves the full names of every ref with the given
+// prefix, sorted.
+func (r *repository) refNames(prefix string) (names []string, err error) {
+	refs, err := r.allRefs()
+	if err != nil {
+		return nil, err
+	}
+	for name := range refs {
+		if strings.HasPrefix(name, prefix) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
@@ -0,0 +1,385 @@
+package main
+
+// Copyright ⓒ 2013 Alexander Bauer and Luke Evers (see LICENSE.md)
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestApplyDelta(t *testing.T) {
+	base := []byte("The quick brown fox jumps over the lazy dog")
+	long := bytes.Repeat([]byte("0123456789abcdef"), 0x1100)
+	tests := []struct {
+		name  string
+		base  []byte
+		delta []byte
+		want  string
+		err   bool // Whether the delta is invalid
+	}{{
+		name:  "insert",
+		base:  base,
+		delta: append([]byte{43, 5, 5}, "hello"...),
+		want:  "hello",
+	}, {
+		name:  "copy",
+		base:  base,
+		delta: []byte{43, 9, 0x91, 4, 5, 0x91, 39, 4},
+		want:  "quick dog",
+	}, {
+		name:  "copy and insert",
+		base:  base,
+		delta: []byte{43, 43, 0x90, 16, 3, 'c', 'a', 't', 0x91, 19, 24},
+		want:  "The quick brown cat jumps over the lazy dog",
+	}, {
+		name:  "whole base",
+		base:  base,
+		delta: []byte{43, 43, 0x90, 43},
+		want:  string(base),
+	}, {
+		name: "long",
+		base: long,
+		// The sizes are 0x11000 and 0x10010, so each takes three
+		// bytes, and a copy without a size copies 0x10000 bytes.
+		delta: []byte{0x80, 0xa0, 0x04, 0x90, 0x80, 0x04,
+			0x81, 0x10, 0x91, 0x10, 0x10},
+		want: string(long[0x10:0x10010]) + string(long[0x10:0x20]),
+	}, {
+		name:  "offset in two bytes",
+		base:  long,
+		delta: []byte{0x80, 0xa0, 0x04, 4, 0x93, 0x05, 0x01, 4},
+		want:  string(long[0x105 : 0x105+4]),
+	}, {
+		name:  "empty",
+		base:  base,
+		delta: []byte{43, 0},
+		want:  "",
+	}, {
+		name:  "wrong base size",
+		base:  base,
+		delta: []byte{42, 5, 0x90, 5},
+		err:   true,
+	}, {
+		name:  "copy past base",
+		base:  base,
+		delta: []byte{43, 5, 0x91, 40, 5},
+		err:   true,
+	}, {
+		name:  "missing copy arguments",
+		base:  base,
+		delta: []byte{43, 5, 0x91, 40},
+		err:   true,
+	}, {
+		name:  "short insert",
+		base:  base,
+		delta: append([]byte{43, 5, 5}, "hell"...),
+		err:   true,
+	}, {
+		name:  "reserved instruction",
+		base:  base,
+		delta: []byte{43, 5, 0},
+		err:   true,
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := applyDelta(test.base, test.delta)
+			if test.err {
+				if err == nil {
+					t.Errorf("got %q, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != test.want {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+// TestNative reads every object, tree, file, and ref of a small
+// repository with the native reader, and compares them with what git
+// gives. It does so once while the objects are loose, and again after
+// 'git gc' has packed them, many as deltas.
+func TestNative(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	dir, err := ioutil.TempDir("", "grove-native")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	makeTestRepository(t, dir)
+
+	for _, stage := range []string{"loose", "packed"} {
+		if stage == "packed" {
+			testGit(t, dir, "gc", "-q")
+		}
+		t.Run(stage, func(t *testing.T) {
+			r, err := openRepository(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stage == "packed" && len(r.packs) == 0 {
+				t.Fatal("no packs were found")
+			}
+			t.Run("objects", func(t *testing.T) { testNativeObjects(t, r, dir) })
+			t.Run("trees", func(t *testing.T) { testNativeTrees(t, r, dir) })
+			t.Run("log", func(t *testing.T) { testNativeLog(t, r, dir) })
+			t.Run("refs", func(t *testing.T) { testNativeRefs(t, r, dir) })
+		})
+	}
+}
+
+// makeTestRepository creates a repository with a merge of three
+// branches, a file which changes a little in every commit, so that it
+// packs as deltas, a binary file, subdirectories, and tags. Most
+// commits share the same date, so that the order in which they are
+// walked depends on more than their dates.
+func makeTestRepository(t *testing.T, dir string) {
+	testGit(t, dir, "init", "-q")
+	testGit(t, dir, "symbolic-ref", "HEAD", "refs/heads/master")
+	var lines []string
+	for i := 0; i < 200; i++ {
+		lines = append(lines, fmt.Sprintf("line %d of a file long enough to be packed as deltas", i))
+	}
+	binary := make([]byte, 256)
+	for i := range binary {
+		binary[i] = byte(i)
+	}
+	commit := func(date int, message string, files map[string]string) {
+		for name, contents := range files {
+			p := path.Join(dir, name)
+			if err := os.MkdirAll(path.Dir(p), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := ioutil.WriteFile(p, []byte(contents), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		testGit(t, dir, "add", "-A")
+		testGitDate(t, dir, date, "commit", "-q", "-m", message)
+	}
+
+	commit(1, "Initial commit", map[string]string{
+		"long":           strings.Join(lines, "\n"),
+		"binary":         string(binary),
+		"sub/dir/nested": "nested\n",
+		"sub/file":       "file\n",
+	})
+	testGit(t, dir, "branch", "side")
+	testGit(t, dir, "branch", "other")
+	for i := 1; i <= 4; i++ {
+		lines[i*10] = fmt.Sprintf("changed on master %d", i)
+		commit(2, fmt.Sprintf("Master %d", i), map[string]string{
+			"long": strings.Join(lines, "\n"),
+		})
+	}
+	testGitDate(t, dir, 2, "tag", "-a", "-m", "Annotated", "v1")
+	for _, branch := range []string{"side", "other"} {
+		testGit(t, dir, "checkout", "-q", branch)
+		for i := 1; i <= 4; i++ {
+			commit(2, fmt.Sprintf("%s %d", branch, i), map[string]string{
+				"sub/" + branch: strings.Repeat(branch+"\n", i),
+			})
+		}
+	}
+	testGit(t, dir, "tag", "light", "side")
+	testGit(t, dir, "checkout", "-q", "master")
+	testGitDate(t, dir, 3, "merge", "-q", "--no-ff", "-m", "Merge side and other",
+		"side", "other")
+	commit(3, "After the merge", map[string]string{
+		"sub/dir/nested": "nested again\n",
+	})
+}
+
+// testGit runs git in dir, and fails the test if it does not succeed.
+func testGit(t *testing.T, dir string, args ...string) string {
+	return testGitDate(t, dir, 0, args...)
+}
+
+// testGitDate runs git in dir, as testGit, with the dates of any
+// commits or tags made set to the given number of days after a fixed
+// time.
+func testGitDate(t *testing.T, dir string, date int, args ...string) string {
+	when := fmt.Sprintf("@%d +0000", 1370000000+date*86400)
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(),
+		"GIT_CONFIG_NOSYSTEM=1", "GIT_CONFIG_GLOBAL=/dev/null",
+		"GIT_AUTHOR_NAME=Luke", "GIT_AUTHOR_EMAIL=luke@example.com",
+		"GIT_COMMITTER_NAME=Alexander", "GIT_COMMITTER_EMAIL=alexander@example.com",
+		"GIT_AUTHOR_DATE="+when, "GIT_COMMITTER_DATE="+when)
+	output, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("git %s: %v", strings.Join(args, " "), err)
+	}
+	return string(output)
+}
+
+// testNativeObjects reads every object in the repository, and compares
+// its type and contents with those given by 'git cat-file'.
+func testNativeObjects(t *testing.T, r *repository, dir string) {
+	objects := strings.Fields(testGit(t, dir, "cat-file",
+		"--batch-all-objects", "--batch-check=%(objectname)"))
+	if len(objects) == 0 {
+		t.Fatal("no objects were found")
+	}
+	for _, sha := range objects {
+		o, err := r.read(sha)
+		if err != nil {
+			t.Errorf("%s: %v", sha, err)
+			continue
+		}
+		typ := strings.TrimSpace(testGit(t, dir, "cat-file", "-t", sha))
+		data := testGit(t, dir, "cat-file", typ, sha)
+		if o.Type != typ || string(o.Data) != data {
+			t.Errorf("%s: got %s of %d bytes, want %s of %d bytes",
+				sha, o.Type, len(o.Data), typ, len(data))
+		}
+	}
+}
+
+// testNativeTrees lists directories and reads files, and compares them
+// with those given by 'git ls-tree' and 'git cat-file'.
+func testNativeTrees(t *testing.T, r *repository, dir string) {
+	g := &git{Path: dir}
+	// Only simple revisions are understood by the native reader.
+	old := strings.TrimSpace(testGit(t, dir, "rev-parse", "HEAD~2"))
+	for _, rev := range []string{"HEAD", "side", "v1", "light", old} {
+		for _, d := range []string{"", "sub", "sub/dir/"} {
+			got, err := r.tree(rev, d)
+			if err != nil {
+				t.Errorf("%s:%s: %v", rev, d, err)
+				continue
+			}
+			// The native reader is opened again by g, so it is
+			// disabled to list the tree with git.
+			*fNative = false
+			want, err := g.ListTree(rev, d)
+			*fNative = true
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("%s:%s: got %s, want %s", rev, d,
+					describeTree(got), describeTree(want))
+			}
+		}
+		for _, file := range []string{"long", "binary", "sub/file", "sub/dir/nested"} {
+			got, err := r.file(rev, file)
+			if err != nil {
+				t.Errorf("%s:%s: %v", rev, file, err)
+				continue
+			}
+			want := testGit(t, dir, "cat-file", "blob", rev+":"+file)
+			if string(got) != want {
+				t.Errorf("%s:%s: got %q, want %q", rev, file, got, want)
+			}
+		}
+	}
+
+	// Directories cannot be read as files, with or without the native
+	// reader.
+	if _, err := r.file("HEAD", "sub"); err != ErrNotFound {
+		t.Errorf("reading a directory natively gave %v, want ErrNotFound", err)
+	}
+	*fNative = false
+	_, err := g.GetFile("HEAD", "sub")
+	*fNative = true
+	if err != ErrNotFound {
+		t.Errorf("reading a directory with git gave %v, want ErrNotFound", err)
+	}
+}
+
+// testNativeLog walks the history, and compares the commits with those
+// given by 'git rev-list', in the same order, and as parsed from
+// 'git log'.
+func testNativeLog(t *testing.T, r *repository, dir string) {
+	merge := strings.TrimSpace(testGit(t, dir, "rev-parse", "HEAD~1"))
+	for _, revs := range [][]string{
+		{"HEAD"}, {"side"}, {"v1"}, {merge}, {"side", "other", "v1"},
+	} {
+		var got []*Commit
+		err := r.log(revs, func(c *Commit) bool {
+			got = append(got, c)
+			return true
+		})
+		if err != nil {
+			t.Errorf("%v: %v", revs, err)
+			continue
+		}
+		var shas []string
+		for _, c := range got {
+			shas = append(shas, c.SHA)
+		}
+		want := strings.Fields(testGit(t, dir, append([]string{"rev-list"}, revs...)...))
+		if !reflect.DeepEqual(shas, want) {
+			t.Errorf("%v: got %v, want %v", revs, shas, want)
+			continue
+		}
+
+		log := gitParseLog(testGit(t, dir, append([]string{"log", "-z",
+			"--format=format:" + gitLogFmt}, revs...)...))
+		for i, c := range got {
+			if !c.AuthorDate.Equal(log[i].AuthorDate) ||
+				!c.CommitDate.Equal(log[i].CommitDate) {
+				t.Errorf("%s: got dates %v and %v, want %v and %v", c.SHA,
+					c.AuthorDate, c.CommitDate, log[i].AuthorDate, log[i].CommitDate)
+			}
+			g, w := *c, *log[i]
+			g.AuthorDate, g.CommitDate, g.Time = w.AuthorDate, w.CommitDate, w.Time
+			if !reflect.DeepEqual(g, w) {
+				t.Errorf("%s: got %+v, want %+v", c.SHA, g, w)
+			}
+		}
+	}
+}
+
+// testNativeRefs resolves refs and abbreviated SHAs, and compares them
+// with those given by 'git rev-parse' and 'git for-each-ref'.
+func testNativeRefs(t *testing.T, r *repository, dir string) {
+	head := strings.TrimSpace(testGit(t, dir, "rev-parse", "HEAD"))
+	for _, rev := range []string{"HEAD", "master", "side", "v1", "light",
+		"refs/heads/side", head, head[:7]} {
+		got, err := r.resolve(rev)
+		if err != nil {
+			t.Errorf("%s: %v", rev, err)
+			continue
+		}
+		if want := strings.TrimSpace(testGit(t, dir, "rev-parse", rev)); got != want {
+			t.Errorf("%s: got %s, want %s", rev, got, want)
+		}
+	}
+
+	names, err := r.refNames("refs/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := strings.Fields(testGit(t, dir, "for-each-ref", "--format=%(refname)"))
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("got refs %v, want %v", names, want)
+	}
+	if target, ok := r.symbolicRef("HEAD"); !ok || target != "refs/heads/master" {
+		t.Errorf("got HEAD pointing to %q, want refs/heads/master", target)
+	}
+}
+
+// describeTree formats tree entries for test failures, as the pointers
+// within them would otherwise be printed.
+func describeTree(entries []*TreeEntry) (s string) {
+	for _, e := range entries {
+		s += fmt.Sprintf("\n\t%+v", *e)
+	}
+	return
+}
@@ -0,0 +1,191 @@
+package main
+
+// Copyright ⓒ 2013 Alexander Bauer and Luke Evers (see LICENSE.md)
+
+import (
+	"errors"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// Policy decides which directories and repositories may be served.
+// Whatever the policy, names beginning with "." are never served, nor
+// are repositories hidden in the configuration.
+type Policy interface {
+	// Dir checks whether a directory which is not a git repository
+	// may be listed.
+	Dir(p string, info os.FileInfo) bool
+
+	// Repository checks whether a git repository may be viewed and
+	// cloned.
+	Repository(p string, info os.FileInfo) bool
+}
+
+// policy is the visibility policy in use. It is selected with the
+// -policy flag.
+var policy Policy = modePolicy(0)
+
+// exportMarker is the file which marks a repository as exported under
+// the marker policy, in the same way as git-daemon-export-ok.
+const exportMarker = ".grove-export"
+
+// newPolicy creates the policy with the given name. The permission
+// mode is used by the "mode" policy, and the allow and deny globs by
+// the "glob" policy.
+func newPolicy(name, perms string, allow, deny []string) (Policy, error) {
+	switch name {
+	case "mode":
+		return parsePerms(perms)
+	case "glob":
+		for _, pattern := range append(allow, deny...) {
+			if _, err := path.Match(pattern, ""); err != nil || !path.IsAbs(pattern) {
+				return nil, errors.New("bad glob " + strconv.Quote(pattern) +
+					"; globs must be absolute paths")
+			}
+		}
+		return &globPolicy{allow, deny}, nil
+	case "marker":
+		return markerPolicy{}, nil
+	case "gitconfig":
+		return gitConfigPolicy{}, nil
+	}
+	return nil, errors.New("unknown policy " + strconv.Quote(name) +
+		"; expected mode, glob, marker, or gitconfig")
+}
+
+// modePolicy serves directories and repositories according to their
+// permission bits. It is 0 to require that they be readable by
+// everyone, 1 by the group, or 2 by the owner.
+type modePolicy uint
+
+// parsePerms interprets the name of a permission mode, which is
+// "world", "group", or "owner".
+func parsePerms(mode string) (modePolicy, error) {
+	switch mode {
+	case "world":
+		return 0, nil
+	case "group":
+		return 1, nil
+	case "owner":
+		return 2, nil
+	}
+	return 0, errors.New("unknown permission mode " + strconv.Quote(mode) +
+		"; expected world, group, or owner")
+}
+
+func (m modePolicy) Dir(p string, info os.FileInfo) bool {
+	return m.readable(info)
+}
+
+func (m modePolicy) Repository(p string, info os.FileInfo) bool {
+	return m.readable(info)
+}
+
+func (m modePolicy) readable(info os.FileInfo) bool {
+	permBits := 0004
+	if info.IsDir() {
+		permBits = 0005
+	}
+
+	// For example, consider the following:
+	//
+	//       rwl rwl rwl       r-l
+	//    0b 111 101 101 & (0b 101 << 3)  > 0
+	//    0b 111 101 101 & 0b 000 101 000 > 0
+	//    0b 000 101 000                  > 0
+	//    TRUE
+	//
+	// Thus, the file is readable and listable by the group, and
+	// therefore okay to serve.
+	return (info.Mode().Perm()&os.FileMode((permBits<<(uint(m)*3))) > 0)
+}
+
+// globPolicy serves repositories whose paths, or those of a directory
+// above them, match one of the allowed globs, if any are given, and
+// none of the denied ones. Directories are listed if they could lead
+// to an allowed repository.
+type globPolicy struct {
+	allow []string // Globs of paths to serve, or none to serve all
+	deny  []string // Globs of paths never to serve
+}
+
+func (g *globPolicy) Dir(p string, info os.FileInfo) bool {
+	if matchAbove(g.deny, p) {
+		return false
+	}
+	if len(g.allow) == 0 || matchAbove(g.allow, p) {
+		return true
+	}
+	// Directories above an allowed path must be listed for it to be
+	// reached, so each glob is matched by as many elements as the
+	// directory has.
+	depth := strings.Count(path.Clean(p), "/")
+	for _, pattern := range g.allow {
+		parts := strings.Split(path.Clean(pattern), "/")
+		if len(parts) <= depth {
+			continue
+		}
+		if ok, _ := path.Match(strings.Join(parts[:depth+1], "/"), p); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *globPolicy) Repository(p string, info os.FileInfo) bool {
+	if matchAbove(g.deny, p) {
+		return false
+	}
+	return len(g.allow) == 0 || matchAbove(g.allow, p)
+}
+
+// matchAbove checks whether the path, or any directory above it,
+// matches one of the globs.
+func matchAbove(patterns []string, p string) bool {
+	for p = path.Clean(p); ; p = path.Dir(p) {
+		for _, pattern := range patterns {
+			if ok, _ := path.Match(pattern, p); ok {
+				return true
+			}
+		}
+		if p == "/" || p == "." {
+			return false
+		}
+	}
+}
+
+// markerPolicy serves only repositories which contain the export
+// marker, either at the top of the working directory or in the .git
+// directory. Directories are always listed.
+type markerPolicy struct{}
+
+func (markerPolicy) Dir(p string, info os.FileInfo) bool {
+	return true
+}
+
+func (markerPolicy) Repository(p string, info os.FileInfo) bool {
+	for _, marker := range []string{
+		path.Join(p, exportMarker),
+		path.Join(p, ".git", exportMarker),
+	} {
+		if _, err := os.Stat(marker); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// gitConfigPolicy serves only repositories whose git configuration
+// sets grove.public to true. Directories are always listed.
+type gitConfigPolicy struct{}
+
+func (gitConfigPolicy) Dir(p string, info os.FileInfo) bool {
+	return true
+}
+
+func (gitConfigPolicy) Repository(p string, info os.FileInfo) bool {
+	g := &git{Path: p}
+	return g.ConfigBool("grove.public")
+}
@@ -0,0 +1,13 @@
+//go:build !unix
+
+package main
+
+// Copyright ⓒ 2013 Alexander Bauer and Luke Evers (see LICENSE.md)
+
+import (
+	"os/exec"
+)
+
+// setProcessGroup does nothing where process groups are not
+// supported. Cancellation kills only git itself.
+func setProcessGroup(cmd *exec.Cmd) {}
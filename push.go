@@ -0,0 +1,186 @@
+package main
+
+// Copyright ⓒ 2013 Alexander Bauer and Luke Evers (see LICENSE.md)
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// defaultPushRefs are the refs to which users may push, if a
+// repository accepts pushes but does not give its own. "<user>" is
+// replaced with the user who is pushing, so that nobody can push to
+// the branches of the owner, nor to those of each other.
+var defaultPushRefs = []string{"refs/heads/incoming/<user>/*"}
+
+// isReceivePack checks whether a request to git-http-backend is part
+// of a push, either advertising the refs to a pushing client, or
+// receiving the push itself.
+func isReceivePack(req *http.Request) bool {
+	return strings.HasSuffix(req.URL.Path, "/git-receive-pack") ||
+		req.URL.Query().Get("service") == "git-receive-pack"
+}
+
+// CanPush checks whether a user, who must have logged in, may push to
+// the repository. Repositories accept pushes only if they set push in
+// the configuration, and then from their pushers, or if none are
+// given, from anyone who may read them. No pushes are accepted if git
+// is too old for receivePackEnv.
+func CanPush(user, repository string) bool {
+	c := repoConfig(repository)
+	if !pushSupported() || len(user) == 0 || !c.Push ||
+		!CanRead(user, repository) {
+		return false
+	}
+	if len(c.Pushers) == 0 {
+		return true
+	}
+	for _, pusher := range c.Pushers {
+		if pusher == user || pusher == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// CanPushRef checks whether a user may update the given ref in the
+// repository, according to its push_refs. A pattern ending in "/*"
+// allows any ref below it, and other patterns are matched as globs.
+func CanPushRef(user, repository, ref string) bool {
+	patterns := repoConfig(repository).PushRefs
+	if len(patterns) == 0 {
+		patterns = defaultPushRefs
+	}
+	for _, pattern := range patterns {
+		pattern = strings.Replace(pattern, "<user>", user, -1)
+		if strings.HasSuffix(pattern, "/*") {
+			prefix := strings.TrimSuffix(pattern, "*")
+			if strings.HasPrefix(ref, prefix) && len(ref) > len(prefix) {
+				return true
+			}
+		} else if ok, _ := path.Match(pattern, ref); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// pushStatus gives the status with which a push request should be
+// answered. Anonymous users are asked to log in, and users who may not
+// push, or who are updating refs outside of those allowed, are
+// refused.
+func pushStatus(req *http.Request, repository string) int {
+	user := requestUser(req)
+	if len(user) == 0 {
+		if setting(&users) != nil {
+			return http.StatusUnauthorized
+		}
+		return http.StatusForbidden
+	}
+	if !CanPush(user, repository) {
+		return http.StatusForbidden
+	}
+	if req.Method != "POST" {
+		return http.StatusOK
+	}
+	refs, err := receivePackRefs(req)
+	if err != nil {
+		l.Printf("Push from %q as %q is malformed: %s\n",
+			req.RemoteAddr, user, err)
+		return http.StatusBadRequest
+	}
+	for _, ref := range refs {
+		if !CanPushRef(user, repository, ref) {
+			l.Printf("Push from %q as %q to %s denied\n",
+				req.RemoteAddr, user, ref)
+			return http.StatusForbidden
+		}
+	}
+	return http.StatusOK
+}
+
+// receivePackRefs reads the refs to be updated from the commands at
+// the start of a git-receive-pack request, which are pkt-lines of the
+// form "<old> <new> <ref>", ending with a flush-pkt. The request body
+// is replaced so that git still receives everything which was read.
+func receivePackRefs(req *http.Request) (refs []string, err error) {
+	var body io.Reader = req.Body
+	if req.Header.Get("Content-Encoding") == "gzip" {
+		// The body is passed on already decompressed.
+		if body, err = gzip.NewReader(req.Body); err != nil {
+			return nil, err
+		}
+		req.Header.Del("Content-Encoding")
+		req.ContentLength = -1
+	}
+
+	var consumed bytes.Buffer
+	r := io.TeeReader(body, &consumed)
+	for {
+		var size [4]byte
+		if _, err = io.ReadFull(r, size[:]); err != nil {
+			return nil, err
+		}
+		n, err := strconv.ParseUint(string(size[:]), 16, 16)
+		if err != nil || (n != 0 && n < 4) {
+			return nil, errors.New("bad pkt-line length")
+		}
+		if n == 0 {
+			break
+		}
+		line := make([]byte, n-4)
+		if _, err = io.ReadFull(r, line); err != nil {
+			return nil, err
+		}
+
+		// The first command is followed by the client's capabilities,
+		// after a NUL.
+		command := string(line)
+		if i := strings.IndexByte(command, 0); i >= 0 {
+			command = command[:i]
+		}
+		command = strings.TrimSuffix(command, "\n")
+		switch {
+		case strings.HasPrefix(command, "shallow "):
+			continue
+		case strings.HasPrefix(command, "push-cert"):
+			return nil, errors.New("signed pushes are not supported")
+		}
+		fields := strings.Fields(command)
+		if len(fields) != 3 {
+			return nil, errors.New("bad command " + strconv.Quote(command))
+		}
+		refs = append(refs, fields[2])
+	}
+
+	req.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(&consumed, body), req.Body}
+	return refs, nil
+}
+
+// pushSupported checks whether git is new enough to be given the
+// environment from receivePackEnv. GIT_CONFIG_COUNT is ignored before
+// git 2.31, which would leave git-http-backend to decide for itself.
+func pushSupported() bool {
+	return gitAtLeast(2, 31)
+}
+
+// receivePackEnv gives the environment which enables or disables
+// pushing through git-http-backend. It is given explicitly either way,
+// because git-http-backend otherwise accepts pushes from any user who
+// has logged in.
+func receivePackEnv(push bool) []string {
+	return []string{
+		"GIT_CONFIG_COUNT=1",
+		"GIT_CONFIG_KEY_0=http.receivepack",
+		"GIT_CONFIG_VALUE_0=" + strconv.FormatBool(push),
+	}
+}
@@ -0,0 +1,176 @@
+package main
+
+// Copyright ⓒ 2013 Alexander Bauer and Luke Evers (see LICENSE.md)
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// pktLine encodes s as a pkt-line, or as a flush-pkt if it is empty.
+func pktLine(s string) string {
+	if len(s) == 0 {
+		return "0000"
+	}
+	return fmt.Sprintf("%04x%s", len(s)+4, s)
+}
+
+func TestReceivePackRefs(t *testing.T) {
+	const (
+		zero   = "0000000000000000000000000000000000000000"
+		before = "1111111111111111111111111111111111111111"
+		after  = "2222222222222222222222222222222222222222"
+	)
+	tests := []struct {
+		name string
+		body string
+		gzip bool
+		refs []string
+		err  bool
+	}{{
+		name: "single",
+		body: pktLine(before+" "+after+" refs/heads/master\x00report-status side-band-64k\n") +
+			pktLine("") + "PACK",
+		refs: []string{"refs/heads/master"},
+	}, {
+		name: "several",
+		body: pktLine(zero+" "+after+" refs/heads/incoming/luke/fix\x00report-status\n") +
+			pktLine(before+" "+zero+" refs/tags/v1\n") +
+			pktLine("") + "PACK",
+		refs: []string{"refs/heads/incoming/luke/fix", "refs/tags/v1"},
+	}, {
+		name: "shallow",
+		body: pktLine("shallow "+before+"\n") +
+			pktLine(before+" "+after+" refs/heads/master\x00report-status\n") +
+			pktLine(""),
+		refs: []string{"refs/heads/master"},
+	}, {
+		name: "gzip",
+		body: pktLine(before+" "+after+" refs/heads/master\x00report-status\n") +
+			pktLine("") + "PACK",
+		gzip: true,
+		refs: []string{"refs/heads/master"},
+	}, {
+		name: "no commands",
+		body: pktLine(""),
+	}, {
+		name: "push certificate",
+		body: pktLine("push-cert\x00report-status\n") + pktLine(""),
+		err:  true,
+	}, {
+		name: "bad command",
+		body: pktLine(before+" refs/heads/master\n") + pktLine(""),
+		err:  true,
+	}, {
+		name: "bad length",
+		body: "00zz" + pktLine(""),
+		err:  true,
+	}, {
+		name: "short length",
+		body: "0003" + pktLine(""),
+		err:  true,
+	}, {
+		name: "truncated",
+		body: pktLine(before + " " + after + " refs/heads/master\n")[:20],
+		err:  true,
+	}, {
+		name: "no flush",
+		body: pktLine(before + " " + after + " refs/heads/master\n"),
+		err:  true,
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			body := []byte(test.body)
+			if test.gzip {
+				var b bytes.Buffer
+				w := gzip.NewWriter(&b)
+				w.Write(body)
+				w.Close()
+				body = b.Bytes()
+			}
+			req, _ := http.NewRequest("POST", "/repo/git-receive-pack",
+				bytes.NewReader(body))
+			if test.gzip {
+				req.Header.Set("Content-Encoding", "gzip")
+			}
+
+			refs, err := receivePackRefs(req)
+			if test.err {
+				if err == nil {
+					t.Fatalf("got refs %q, want an error", refs)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(refs, test.refs) {
+				t.Errorf("got refs %q, want %q", refs, test.refs)
+			}
+
+			// git must still receive the whole request.
+			rest, err := io.ReadAll(req.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(rest) != test.body {
+				t.Errorf("got body %q, want %q", rest, test.body)
+			}
+			if test.gzip && len(req.Header.Get("Content-Encoding")) != 0 {
+				t.Error("Content-Encoding was not removed")
+			}
+		})
+	}
+}
+
+func TestCanPushRef(t *testing.T) {
+	const repository = "/srv/git/repo"
+	tests := []struct {
+		name     string
+		pushRefs []string
+		user     string
+		ref      string
+		want     bool
+	}{
+		{"default", nil, "luke", "refs/heads/incoming/luke/fix", true},
+		{"default nested", nil, "luke", "refs/heads/incoming/luke/a/b", true},
+		{"default other user", nil, "luke", "refs/heads/incoming/alex/fix", false},
+		{"default bare prefix", nil, "luke", "refs/heads/incoming/luke/", false},
+		{"default user prefix", nil, "luke", "refs/heads/incoming/lukewarm/fix", false},
+		{"default master", nil, "luke", "refs/heads/master", false},
+		{"default tag", nil, "luke", "refs/tags/v1", false},
+		{"prefix", []string{"refs/heads/review/*"}, "luke", "refs/heads/review/x", true},
+		{"prefix outside", []string{"refs/heads/review/*"}, "luke", "refs/heads/incoming/luke/x", false},
+		{"exact", []string{"refs/heads/master"}, "luke", "refs/heads/master", true},
+		{"exact other", []string{"refs/heads/master"}, "luke", "refs/heads/main", false},
+		{"glob", []string{"refs/tags/v[0-9]*"}, "luke", "refs/tags/v12", true},
+		{"glob one level", []string{"refs/heads/feature-?"}, "luke", "refs/heads/feature-a/b", false},
+		{"several", []string{"refs/heads/master", "refs/heads/<user>/*"}, "alex", "refs/heads/alex/x", true},
+	}
+	defer func(saved map[string]*RepoConfig) {
+		settingsLock.Lock()
+		repoConfigs = saved
+		settingsLock.Unlock()
+	}(repoConfigs)
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			settingsLock.Lock()
+			repoConfigs = map[string]*RepoConfig{
+				repository: {Push: true, PushRefs: test.pushRefs},
+			}
+			settingsLock.Unlock()
+			got := CanPushRef(test.user, repository, test.ref)
+			if got != test.want {
+				t.Errorf("CanPushRef(%q, %q) with push_refs %q = %v, want %v",
+					test.user, test.ref, strings.Join(test.pushRefs, " "),
+					got, test.want)
+			}
+		})
+	}
+}
@@ -5,20 +5,28 @@ package main
 import (
 	"compress/gzip"
 	"io"
+	"net"
 	"net/http"
 	"net/http/cgi"
 	"os"
 	"path"
+	"path/filepath"
+	"strconv"
 	"strings"
 )
 
-var (
-	Perms = uint(0)
-	// Used to specify which files can be served:
-	// 0: readable globally
-	// 1: readable by group
-	// 2: readable
-)
+// root is a directory which is served, along with everything below
+// it.
+type root struct {
+	Name    string       // Name under which it is served, if not "/"
+	Dir     string       // Directory on the filesystem
+	handler *cgi.Handler // git-http-backend for the repositories in it
+}
+
+// roots are the directories being served. If there is only one, it is
+// served at "/", and otherwise each is served at "/<name>/", where the
+// name is its base name, and "/" lists them.
+var roots []*root
 
 type gzipResponseWriter struct {
 	io.Writer
@@ -26,60 +34,100 @@ type gzipResponseWriter struct {
 }
 
 // Serve creates an HTTP server using net/http and initializes it
-// appropriately.
-func Serve(repodir string) {
-	handler = &cgi.Handler{
-		Path: gitVarExecPath() + "/" + gitHttpBackend,
-		Root: "/",
-		Dir:  repodir,
-		Env: []string{"GIT_PROJECT_ROOT=" + repodir,
-			"GIT_HTTP_EXPORT_ALL=TRUE"},
-		Logger: l,
-	}
+// appropriately, serving each of the given directories on the sockets
+// opened by Listen. If a certificate and key are given, it serves
+// HTTPS instead. It returns once the server has been stopped by a
+// signal.
+func Serve(dirs []string, certFile, keyFile string) {
+	for _, dir := range dirs {
+		r := &root{Dir: dir}
+		if len(dirs) > 1 {
+			r.Name = path.Base(dir)
+		}
+		r.handler = &cgi.Handler{
+			Path: gitVarExecPath() + "/" + gitHttpBackend,
+			Root: "/" + r.Name,
+			Dir:  dir,
+			Env: []string{"GIT_PROJECT_ROOT=" + dir,
+				"GIT_HTTP_EXPORT_ALL=TRUE"},
+			Logger: l,
+		}
+		roots = append(roots, r)
 
-	l.Println("Created CGI handler:",
-		"\n\tPath:\t", handler.Path,
-		"\n\tRoot:\t", handler.Root,
-		"\n\tDir:\t", handler.Dir,
-		"\n\tEnv:\t",
-		"\n\t\t", handler.Env[0],
-		"\n\t\t", handler.Env[1])
+		l.Println("Created CGI handler:",
+			"\n\tPath:\t", r.handler.Path,
+			"\n\tRoot:\t", r.handler.Root,
+			"\n\tDir:\t", r.handler.Dir,
+			"\n\tEnv:\t",
+			"\n\t\t", r.handler.Env[0],
+			"\n\t\t", r.handler.Env[1])
+	}
 
-	l.Println("Starting server on", *fBind+":"+*fPort)
-	http.HandleFunc("/", gzipHandler(HandleWeb))
+	listeners, err := Listen()
+	if err != nil {
+		l.Fatalln("Error listening:", err)
+	}
+	http.HandleFunc("/", gzipHandler(authHandler(HandleWeb)))
+	http.HandleFunc("/search", gzipHandler(authHandler(HandleSearch)))
+	http.HandleFunc(apiPrefix, gzipHandler(authHandler(HandleAPI)))
 	http.HandleFunc("/res/style.css", gzipHandler(HandleCSS))
-	http.HandleFunc("/res/highlight.js", gzipHandler(HandleJS))
 	http.HandleFunc("/favicon.ico", gzipHandler(HandleIcon))
-	err := http.ListenAndServe(*fBind+":"+*fPort, nil)
-	if err != nil {
+
+	// The server stops when it is sent SIGTERM, once the requests in
+	// progress have finished.
+	server := &http.Server{}
+	stopped := make(chan struct{})
+	go handleSignals(server, stopped)
+
+	errs := make(chan error, len(listeners))
+	for _, ln := range listeners {
+		l.Println("Starting server on", ln.Addr().Network(), ln.Addr())
+		go func(ln net.Listener) {
+			if len(certFile) != 0 {
+				errs <- server.ServeTLS(ln, certFile, keyFile)
+			} else {
+				errs <- server.Serve(ln)
+			}
+		}(ln)
+	}
+	if err = <-errs; err != http.ErrServerClosed {
 		l.Fatalln("Server crashed:", err)
 	}
-	return
+	<-stopped
+	l.Println("Server stopped")
 }
 
 // HandleCSS uses http.ServeFile() to serve `style.css` directly from
 // the file system.
 func HandleCSS(w http.ResponseWriter, req *http.Request) {
-	http.ServeFile(w, req, path.Join(*fRes, "style.css"))
-}
-
-// HandleCSS uses http.ServeFile() to serve `highlight.js` directly
-// from the file system.
-func HandleJS(w http.ResponseWriter, req *http.Request) {
-	http.ServeFile(w, req, path.Join(*fRes, "highlight.js"))
+	http.ServeFile(w, req, path.Join(setting(fRes), "style.css"))
 }
 
 // HandleIcon uses http.ServeFile() to serve the favicon directly from
 // the filesystem.
 func HandleIcon(w http.ResponseWriter, req *http.Request) {
-	http.ServeFile(w, req, path.Join(*fRes, "favicon.png"))
+	http.ServeFile(w, req, path.Join(setting(fRes), "favicon.png"))
 }
 
 // HandleWeb handles general requests, such as for the web interface
 // or git-over-http requests.
 func HandleWeb(w http.ResponseWriter, req *http.Request) {
-	// Determine the filesystem path from the URL.
-	p := path.Join(handler.Dir, req.URL.Path)
+	// Determine the filesystem path from the URL. If several roots
+	// are served, "/" lists them.
+	r, p := findRoot(req.URL.Path)
+	if r == nil {
+		status := http.StatusNotFound
+		if path.Clean(req.URL.Path) == "/" {
+			var body string
+			body, status = MakeRootsPage(req)
+			if status == http.StatusOK {
+				w.Write([]byte(body))
+				return
+			}
+		}
+		writeErrorPage(w, req, status)
+		return
+	}
 
 	// Send the request to the git http backend if it is to a .git
 	// URL.
@@ -87,52 +135,174 @@ func HandleWeb(w http.ResponseWriter, req *http.Request) {
 		gitPath := strings.SplitAfter(p, ".git/")[0]
 		l.Printf("Git request to %s from %s\n", req.URL, req.RemoteAddr)
 
-		// Check to make sure that the repository is globally
-		// readable.
-		fi, err := os.Stat(gitPath)
-		if err != nil {
+		// Check to make sure that the policy allows the repository
+		// to be served.
+		repository := strings.TrimSuffix(gitPath, "/.git/")
+		fi, err := os.Stat(repository)
+		if err == nil {
+			_, err = os.Stat(gitPath)
+		}
+		if err != nil || repoConfig(repository).Hidden {
 			l.Printf("Git request of %q from %s produced error: %s\n",
 				req.URL.Path, req.RemoteAddr, err)
 			http.NotFound(w, req)
 			return
 		}
-		if !CheckPermBits(fi) {
+		if !CanServe(repository, fi) {
 			l.Printf("Git request from %q denied: %s\n",
 				req.RemoteAddr, req.URL.Path)
 			http.Error(w, http.StatusText(http.StatusForbidden),
 				http.StatusForbidden)
 			return
 		}
+		// Pushes are refused unless the repository accepts them
+		// from this user, and then only to the refs it allows.
+		push := isReceivePack(req)
+		status := readStatus(req, repository)
+		if status == http.StatusOK && push {
+			status = pushStatus(req, repository)
+		}
+		if status != http.StatusOK {
+			l.Printf("Git request from %q as %q denied: %s\n",
+				req.RemoteAddr, requestUser(req), req.URL.Path)
+			setChallenge(w, status)
+			http.Error(w, http.StatusText(status), status)
+			return
+		}
 
-		handler.ServeHTTP(w, req)
+		// git-http-backend counts towards the limit on git
+		// processes, but not towards that of the repository.
+		release, err := gitLimiter.AcquireTransfer(req.Context())
+		if err != nil {
+			l.Printf("Git request from %s refused: %s\n", req.RemoteAddr, err)
+			setRetryAfter(w, http.StatusServiceUnavailable)
+			http.Error(w, http.StatusText(http.StatusServiceUnavailable),
+				http.StatusServiceUnavailable)
+			return
+		}
+		defer release()
+
+		// The user who logged in, if any, is passed on to
+		// git-http-backend, which gives it to hooks.
+		h := *r.handler
+		h.Env = append(h.Env[:len(h.Env):len(h.Env)],
+			receivePackEnv(push)...)
+		if user := requestUser(req); len(user) != 0 {
+			h.Env = append(h.Env, "REMOTE_USER="+user)
+		}
+		h.ServeHTTP(w, req)
 		return
 	}
 	l.Printf("View of %q from %s\n", req.URL.Path, req.RemoteAddr)
 
 	// Figure out which directory is being requested, and check
 	// whether we're allowed to serve it.
-	repository, file, isFile, status := SplitRepository(handler.Dir, p)
+	repository, file, view, status := SplitRepository(r.Dir, p)
+	if status == http.StatusOK {
+		status = readStatus(req, repository)
+	}
+	if status == http.StatusOK && view == "archive" {
+		// Archives are streamed directly, rather than being built
+		// as a page.
+		ServeArchive(w, req, repository, file)
+		return
+	}
 	if status == http.StatusOK {
 		var body string
-		body, status = MakePage(req, repository, file, isFile)
+		body, status = MakePage(req, repository, file, view)
 		if status == http.StatusOK {
 			w.Write([]byte(body))
 			return
 		}
+		// Clients of the JSON interface are given the error as
+		// JSON, rather than as a page.
+		if isJSONRequest(req) {
+			l.Println("Sending", req.RemoteAddr, "status:", status)
+			if len(body) == 0 {
+				body = "{\"Status\":" + strconv.Itoa(status) + "}"
+			}
+			setRetryAfter(w, status)
+			setChallenge(w, status)
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(status)
+			w.Write([]byte(body))
+			return
+		}
 	}
 
 	// If MakePage gives the status as anything other than 200 OK,
-	// write the error in the header.
+	// write the error in the header, and describe it in an error
+	// page.
+	writeErrorPage(w, req, status)
+}
+
+// writeErrorPage sends the given status, along with a page describing
+// it.
+func writeErrorPage(w http.ResponseWriter, req *http.Request, status int) {
 	l.Println("Sending", req.RemoteAddr, "status:", status)
-	http.Error(w, "Could not serve "+req.URL.Path+"\n"+http.StatusText(status),
-		status)
+	setRetryAfter(w, status)
+	setChallenge(w, status)
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	w.Write([]byte(MakeErrorPage(req, status)))
+}
+
+// HandleSearch searches every servable repository below the top level
+// directory for the query given in the "q" form value.
+func HandleSearch(w http.ResponseWriter, req *http.Request) {
+	l.Printf("Search for %q from %s\n", req.FormValue("q"), req.RemoteAddr)
+	body, status := MakeRootSearchPage(req)
+	if status != http.StatusOK {
+		http.Error(w, http.StatusText(status), status)
+		return
+	}
+	w.Write([]byte(body))
+}
+
+// ServeArchive streams an archive of the repository at a particular
+// ref. The file is given as <ref>.tar.gz or <ref>.zip.
+func ServeArchive(w http.ResponseWriter, req *http.Request, repository, file string) {
+	var ref, format, contentType string
+	switch {
+	case strings.HasSuffix(file, ".tar.gz"):
+		ref = strings.TrimSuffix(file, ".tar.gz")
+		format, contentType = "tar.gz", "application/x-gzip"
+	case strings.HasSuffix(file, ".zip"):
+		ref = strings.TrimSuffix(file, ".zip")
+		format, contentType = "zip", "application/zip"
+	}
+
+	g := &git{
+		Path: repository,
+		ctx:  req.Context(),
+	}
+	if len(format) == 0 || !g.RefExists(ref) {
+		http.NotFound(w, req)
+		return
+	}
+
+	// The archive is named after the repository and the ref, and
+	// unpacks into a directory of the same name.
+	name := path.Base(repository) + "-" + strings.Replace(ref, "/", "-", -1)
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition",
+		"attachment; filename=\""+name+"."+format+"\"")
+
+	l.Printf("Archive of %q at %s from %s\n", repository, ref, req.RemoteAddr)
+	err := g.Archive(w, ref, format, name+"/")
+	if err != nil {
+		l.Printf("Archive of %q at %s produced error: %s\n",
+			repository, ref, err)
+	}
 }
 
 // If the client accepts gzipped responses, that's what we'll send,
 // otherwise use the default http handler to send data.
 func gzipHandler(fn http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+		// Archives are already compressed, so they are sent as-is.
+		if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") ||
+			isArchive(r.URL.Path) {
 			fn(w, r)
 			return
 		}
@@ -144,18 +314,28 @@ func gzipHandler(fn http.HandlerFunc) http.HandlerFunc {
 }
 
 func (w gzipResponseWriter) Write(b []byte) (int, error) {
-	w.Header().Set("content-type", http.DetectContentType(b))
+	if len(w.Header().Get("content-type")) == 0 {
+		w.Header().Set("content-type", http.DetectContentType(b))
+	}
 	return w.Writer.Write(b)
 }
 
+// isArchive checks whether the given URL path is a request for an
+// archive of a repository.
+func isArchive(p string) bool {
+	return strings.Contains(p, "/archive/") &&
+		(strings.HasSuffix(p, ".tar.gz") || strings.HasSuffix(p, ".zip"))
+}
+
 // SplitRepository checks each directory in the path (p), traversing
 // upward, until it finds a .git folder. If the parent directory of
-// this .git directory is not permissable to serve (globally readable
-// and listable, by default), or a .git directory could not be found,
-// or the path is invalid, this function will return an appropriate
-// exit code.  This function will only recurse upward until it reaches
-// the path indicated by toplevel.
-func SplitRepository(toplevel, p string) (repository, file string, isFile bool, status int) {
+// this .git directory may not be served under the current policy, or
+// a .git directory could not be found, or the path is invalid, this
+// function will return an appropriate exit code.  This function will only recurse upward until it reaches
+// the path indicated by toplevel. The view is the first element of
+// the path within the repository, such as "blob" or "tree", and is
+// empty if the repository itself was requested.
+func SplitRepository(toplevel, p string) (repository, file, view string, status int) {
 	path.Clean(toplevel)
 	// Set the repository to the path for the moment, to simplify the
 	// loop
@@ -170,11 +350,17 @@ func SplitRepository(toplevel, p string) (repository, file string, isFile bool,
 			repository = path.Dir(repository)
 		}
 
-		// Check if we shouldn't continue.
+		// Check if we shouldn't continue. The directory being listed
+		// must itself be servable, though any error in finding it is
+		// left to the caller.
 		if repository == toplevel {
 			repository = path.Join(repository, file)
 			file = ""
 			status = http.StatusOK
+			if fi, err := os.Stat(repository); err == nil &&
+				repository != toplevel && !CanServe(repository, fi) {
+				status = http.StatusForbidden
+			}
 			return
 		}
 
@@ -196,36 +382,39 @@ func SplitRepository(toplevel, p string) (repository, file string, isFile bool,
 			return
 		}
 
+		// Hidden repositories are treated as though they do not
+		// exist.
+		if repoConfig(repository).Hidden {
+			status = http.StatusNotFound
+			return
+		}
+
 		// If all is well, check if it's servable.
-		if !CheckPerms(fi) {
+		if !CanServe(repository, fi) {
 			// If not, 403 Forbidden.
 			status = http.StatusForbidden
 			return
 		}
 
-		// If the file is prefixed with /blob/, then treat it as a
-		// file. If it has /tree/, then treat it as a directory. In
-		// either case, chop off the prefix.  If it has neither, 404.
+		// The first element of the file is the view, such as /blob/
+		// or /tree/. Chop it off, and treat the remainder according
+		// to the view. If it is not a known view, 404.
 		if len(file) != 0 {
 			// The trailing slash trickery involves avoiding runtime
 			// errors and splitting the strings sanely.
-			file += "/"
-			if strings.HasPrefix(file, "blob/") {
-				file = strings.SplitAfterN(file, "/", 2)[1]
-				isFile = true
+			parts := strings.SplitN(file+"/", "/", 2)
+			view, file = parts[0], parts[1]
+			switch view {
+			case "blob", "raw", "blame", "commit", "compare",
+				"branches", "tags", "archive", "log", "search":
 				file = strings.TrimRight(file, "/")
-			} else if strings.HasPrefix(file, "tree/") {
-				// Remove the /tree/, but be sure that, if the file is
-				// blank, to make it "/" instead.
-				file = strings.SplitAfterN(file, "/", 2)[1]
+			case "tree":
+				// Be sure that, if the file is blank, to make it "/"
+				// instead.
 				if len(file) == 0 {
 					file = "./"
 				}
-			} else if strings.HasPrefix(file, "raw/") {
-				file = strings.SplitAfterN(file, "/", 2)[1]
-				isFile = true
-				file = strings.TrimRight(file, "/")
-			} else {
+			default:
 				status = http.StatusNotFound
 				return
 			}
@@ -233,33 +422,87 @@ func SplitRepository(toplevel, p string) (repository, file string, isFile bool,
 		status = http.StatusOK
 		return
 	}
-	// Something is very wrong if we get here.
-	status = http.StatusInternalServerError
+}
+
+// FindRepositories walks the directory tree below toplevel, and
+// returns the path of every git repository which may be served. It
+// does not descend into directories which may not be served, nor into
+// the repositories themselves.
+func FindRepositories(toplevel string) (repositories []string) {
+	filepath.Walk(toplevel, func(p string, info os.FileInfo, err error) error {
+		if err != nil || info == nil || !info.IsDir() {
+			return nil
+		}
+		if p != toplevel && !CanServe(p, info) {
+			return filepath.SkipDir
+		}
+		if git, _ := isGit(p); git {
+			repositories = append(repositories, p)
+			return filepath.SkipDir
+		}
+		return nil
+	})
 	return
 }
 
-func CheckPerms(info os.FileInfo) (canServe bool) {
-	if strings.HasPrefix(info.Name(), ".") {
-		return false
+// findRoot retrieves the root which serves the given URL path, and
+// the path on the filesystem to which it refers. If no root serves it,
+// it returns nil.
+func findRoot(urlPath string) (r *root, p string) {
+	urlPath = path.Clean("/" + urlPath)
+	if len(roots) == 1 {
+		return roots[0], path.Join(roots[0].Dir, urlPath)
+	}
+	name, rest := urlPath[1:], "/"
+	if i := strings.Index(name, "/"); i >= 0 {
+		name, rest = name[:i], name[i:]
+	}
+	for _, r := range roots {
+		if r.Name == name {
+			return r, path.Join(r.Dir, rest)
+		}
 	}
-	return CheckPermBits(info)
+	return nil, ""
 }
 
-func CheckPermBits(info os.FileInfo) (canServe bool) {
-	permBits := 0004
-	if info.IsDir() {
-		permBits = 0005
+// servedPath retrieves the URL path at which a directory on the
+// filesystem is served, such as "/dev/grove". The directory of the
+// only root is served at "", rather than "/".
+func servedPath(dir string) string {
+	for _, r := range roots {
+		if rest, ok := withinDir(dir, r.Dir); ok {
+			if len(r.Name) != 0 {
+				return "/" + r.Name + rest
+			}
+			return rest
+		}
 	}
+	return ""
+}
 
-	// For example, consider the following:
-	// 
-	//       rwl rwl rwl       r-l
-	//    0b 111 101 101 & (0b 101 << 3)  > 0
-	//    0b 111 101 101 & 0b 000 101 000 > 0
-	//    0b 000 101 000                  > 0
-	//    TRUE
-	// 
-	// Thus, the file is readable and listable by the group, and
-	// therefore okay to serve.
-	return (info.Mode().Perm()&os.FileMode((permBits<<(Perms*3))) > 0)
+// withinDir checks whether the path p is the directory dir, or below
+// it, and if so, gives the remainder of the path, such as "/sub/repo".
+func withinDir(p, dir string) (rest string, ok bool) {
+	dir = strings.TrimSuffix(dir, "/")
+	if p == dir {
+		return "", true
+	}
+	if strings.HasPrefix(p, dir+"/") {
+		return p[len(dir):], true
+	}
+	return "", false
+}
+
+// CanServe checks whether the directory at p, which has the given
+// info, may be served under the current policy. Names beginning with
+// "." are never served, nor are repositories hidden in the
+// configuration.
+func CanServe(p string, info os.FileInfo) bool {
+	if strings.HasPrefix(info.Name(), ".") {
+		return false
+	}
+	if git, _ := isGit(p); git {
+		return !repoConfig(p).Hidden && setting(&policy).Repository(p, info)
+	}
+	return setting(&policy).Dir(p, info)
 }
@@ -0,0 +1,62 @@
+package main
+
+// Copyright ⓒ 2013 Alexander Bauer and Luke Evers (see LICENSE.md)
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// handleSignals waits for signals to the process. SIGHUP reloads the
+// configuration, and SIGTERM or an interrupt shuts the server down,
+// waiting up to -shutdown-timeout for the requests in progress,
+// including git transfers, to finish. Once the server has stopped,
+// stopped is closed.
+func handleSignals(server *http.Server, stopped chan<- struct{}) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP, syscall.SIGTERM, os.Interrupt)
+	for sig := range signals {
+		if sig == syscall.SIGHUP {
+			if err := Reload(); err != nil {
+				l.Println("Error reloading configuration:", err)
+			}
+			continue
+		}
+
+		// A second signal stops the process at once, as usual.
+		signal.Stop(signals)
+		timeout := setting(fShutdownTimeout)
+		l.Printf("Received %s, waiting up to %s for requests to finish\n",
+			sig, timeout)
+		ctx := context.Background()
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		if err := server.Shutdown(ctx); err != nil {
+			l.Println("Stopping with requests in progress:", err)
+			server.Close()
+		}
+		close(stopped)
+		return
+	}
+}
+
+// Reload reads the configuration file again, and applies it along with
+// the flags given on the command line. Templates and resources are
+// read as they are needed, so changes to them, or to their
+// directories, are also picked up. Settings named in restartFlags keep
+// their current values. If the configuration is invalid, the current
+// one is kept.
+func Reload() error {
+	l.Println("Reloading configuration")
+	config, err := LoadConfig(*fConfig)
+	if err != nil {
+		return err
+	}
+	return Configure(config, true)
+}
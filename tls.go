@@ -0,0 +1,180 @@
+package main
+
+// Copyright ⓒ 2013 Alexander Bauer and Luke Evers (see LICENSE.md)
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// TLSFiles retrieves the certificate and key with which to serve
+// HTTPS, as given by -tls-cert and -tls-key. With -tls-auto, they
+// default to files in the configuration directory, and if they do not
+// exist, a self-signed certificate is generated and saved there, so
+// that it stays the same from one run to the next. If TLS is not
+// enabled, both are empty.
+func TLSFiles() (cert, key string, err error) {
+	cert, key = *fTLSCert, *fTLSKey
+	if !*fTLSAuto {
+		if (len(cert) == 0) != (len(key) == 0) {
+			return "", "", errors.New("-tls-cert and -tls-key must be given together")
+		}
+		return cert, key, nil
+	}
+
+	dir := filepath.Dir(defaultConfigPath())
+	if len(cert) == 0 {
+		cert = filepath.Join(dir, "tls-cert.pem")
+	}
+	if len(key) == 0 {
+		key = filepath.Join(dir, "tls-key.pem")
+	}
+	_, certErr := os.Stat(cert)
+	_, keyErr := os.Stat(key)
+	switch {
+	case certErr == nil && keyErr == nil:
+		return cert, key, nil
+	case !os.IsNotExist(certErr) || !os.IsNotExist(keyErr):
+		// If only one of the pair exists, it is not replaced.
+		return "", "", fmt.Errorf("%s and %s must both exist, or neither",
+			cert, key)
+	}
+	l.Println("Generating self-signed certificate", cert)
+	return cert, key, generateCert(cert, key)
+}
+
+// generateCert creates a self-signed certificate for this host, valid
+// for its names and addresses, and writes it and its private key to
+// the given files. The certificate may sign others, so that git and
+// curl accept it as its own authority when it is given as
+// http.sslCAInfo.
+func generateCert(certFile, keyFile string) error {
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return err
+	}
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "localhost"
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			CommonName:   hostname,
+			Organization: []string{"grove"},
+		},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().AddDate(10, 0, 0),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		DNSNames:              []string{hostname},
+	}
+	if hostname != "localhost" {
+		template.DNSNames = append(template.DNSNames, "localhost")
+	}
+
+	// Peers on the LAN are as likely to use an address as a name, so
+	// every address of this host is included.
+	template.IPAddresses = []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback}
+	if addrs, err := net.InterfaceAddrs(); err == nil {
+		for _, addr := range addrs {
+			if ipnet, ok := addr.(*net.IPNet); ok && !ipnet.IP.IsLoopback() {
+				template.IPAddresses = append(template.IPAddresses, ipnet.IP)
+			}
+		}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template,
+		&priv.PublicKey, priv)
+	if err != nil {
+		return err
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		return err
+	}
+
+	for _, dir := range []string{filepath.Dir(certFile), filepath.Dir(keyFile)} {
+		if err = os.MkdirAll(dir, 0700); err != nil {
+			return err
+		}
+	}
+	err = writePEM(keyFile, "PRIVATE KEY", keyDER, 0600)
+	if err != nil {
+		return err
+	}
+	return writePEM(certFile, "CERTIFICATE", der, 0644)
+}
+
+// writePEM writes a single PEM block to a new file with the given
+// permissions.
+func writePEM(file, blockType string, der []byte, perm os.FileMode) error {
+	f, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return err
+	}
+	err = pem.Encode(f, &pem.Block{Type: blockType, Bytes: der})
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// certFingerprint retrieves the SHA-256 fingerprint of the first
+// certificate in the given file, in the colon-separated form shown by
+// 'openssl x509 -fingerprint -sha256'.
+func certFingerprint(certFile string) (string, error) {
+	b, err := ioutil.ReadFile(certFile)
+	if err != nil {
+		return "", err
+	}
+	block, _ := pem.Decode(b)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return "", errors.New(certFile + " does not contain a certificate")
+	}
+	sum := sha256.Sum256(block.Bytes)
+	hex := make([]string, len(sum))
+	for i, b := range sum {
+		hex[i] = fmt.Sprintf("%02X", b)
+	}
+	return strings.Join(hex, ":"), nil
+}
+
+// requestScheme retrieves the scheme by which a request was made,
+// which is used to build absolute URLs. Requests on a Unix socket come
+// from a proxy, which may have received them over HTTPS, so its
+// X-Forwarded-Proto is trusted.
+func requestScheme(req *http.Request) string {
+	if req.TLS != nil {
+		return "https"
+	}
+	if isUnixRequest(req) {
+		switch proto := req.Header.Get("X-Forwarded-Proto"); proto {
+		case "http", "https":
+			return proto
+		}
+	}
+	return "http"
+}
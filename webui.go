@@ -5,18 +5,35 @@ package main
 import (
 	"bytes"
 	"encoding/base64"
+	"errors"
 	"github.com/russross/blackfriday"
 	"html"
 	"html/template"
 	"net/http"
+	"net/url"
 	"os"
 	"path"
 	"strconv"
 	"strings"
 )
 
+// gitDateFmt is the format in which absolute times are displayed. It
+// is the same as git's default date format.
+const gitDateFmt = "Mon Jan 2 15:04:05 2006 -0700"
+
+// grepMaxMatches is the most matching lines shown by a search of a
+// repository, beyond which the results are truncated.
+const grepMaxMatches = 500
+
+// searchMaxRepositories is the most repositories whose code is searched
+// by a search of the top level. Beyond it, and once grepMaxMatches
+// lines have been found in all of them together, only the names and
+// READMEs of repositories are searched, and the results are truncated.
+const searchMaxRepositories = 50
+
 type gitPage struct {
 	Owner     string
+	Scheme    string
 	BasePath  string
 	URL       string
 	GitDir    string
@@ -30,17 +47,72 @@ type gitPage struct {
 	List      []*dirList
 	Logs      []*gitLog
 	Location  template.URL
+	Parent    template.URL
 	Numbers   template.HTML
 	Version   string
+	Commit    *gitLog
+	Diffs     []*fileDiff
+	Blame     []*blameLine
+	Base      string
+	Head      string
+	Added     int
+	Deleted   int
+	RefKind   string
+	Refs      []*Ref
+	Ref       string
+	RefNames  []string
+	History   *historyPage
+	Query     string
+	Results   []*GrepFile
+	Truncated bool
+	Code      bool
+	Matches   []*repoMatch
+	Status    int
+	Message   string
 }
 
 type gitLog struct {
-	Author    string
-	Classtype string
-	SHA       string
-	Time      string
-	Subject   template.HTML
-	Body      template.HTML
+	Author         string
+	Email          string
+	Date           string // Absolute time at which the commit was authored
+	Committer      string
+	CommitterEmail string
+	CommitDate     string // Absolute time at which the commit was made
+	Classtype      string
+	SHA            string
+	Tree           string
+	Parents        []string
+	Time           string
+	Subject        template.HTML
+	Body           template.HTML
+}
+
+type historyPage struct {
+	Author string       // Author filter
+	Since  string       // Earliest date filter
+	Until  string       // Latest date filter
+	Total  int          // Number of commits, if not filtered
+	Prev   template.URL // Link to the newer page, if any
+	Next   template.URL // Link to the older page, if any
+}
+
+type repoMatch struct {
+	Name      string // Path of the repository relative to the top level
+	URL       template.URL
+	NameMatch bool        // Whether the name contains the query
+	Readme    []string    // Lines of the README containing the query
+	Code      []*GrepFile // Files containing the query
+}
+
+type blameLine struct {
+	Number  string // Line number in the file
+	SHA     string // Full SHA of the commit which last touched the line
+	Short   string // Short form of SHA
+	Author  string
+	Time    string
+	Summary string
+	First   bool // Whether this line begins a run from one commit
+	Text    string
 }
 
 type dirList struct {
@@ -58,10 +130,19 @@ type dirList struct {
 // the repository at a particular ref, HTML escapes it, converts any
 // markdown to HTML, and returns it as a string. It is intended for
 // use with READMEs, but could potentially be used for other files.
-func getREADME(g *git, ref, file string) string {
-	readme := g.GetFile(ref, file)
+func getREADME(g *git, ref, file string) (string, error) {
+	key := g.cacheKey("readme", ref, file)
+	if v, ok := queryCache.Get(key); ok {
+		return v.(string), nil
+	}
+	readme, err := g.GetFile(ref, file)
+	if err != nil {
+		return "", err
+	}
 	readme = []byte(html.EscapeString(string(readme)))
-	return string(blackfriday.MarkdownCommon(readme))
+	rendered := string(blackfriday.MarkdownCommon(readme))
+	queryCache.Add(key, rendered, len(rendered))
+	return rendered, nil
 }
 
 // Check for a .git directory in the repository argument. If one does
@@ -79,30 +160,78 @@ func isGit(repository string) (git bool, gitDir string) {
 
 // Retrieval of file info is done in two steps so that we can use
 // os.Stat(), rather than os.Lstat(), the former of which follows
-// symlinks.
-func MakeDirInfos(repository string, dirnames []string) (dirinfos []os.FileInfo) {
+// symlinks. Repositories which the user may not read are left out.
+func MakeDirInfos(repository, user string, dirnames []string) (dirinfos []os.FileInfo) {
 	dirinfos = make([]os.FileInfo, 0, len(dirnames))
 	for _, n := range dirnames {
-		info, err := os.Stat(repository + "/" + n)
-		if err == nil && CheckPerms(info) {
+		p := repository + "/" + n
+		info, err := os.Stat(p)
+		if err == nil && CanServe(p, info) && CanRead(user, p) {
 			dirinfos = append(dirinfos, info)
 		}
 	}
 	return
 }
 
-func MakePage(req *http.Request, repository string, file string, isFile bool) (page string, status int) {
+func MakePage(req *http.Request, repository string, file string, view string) (page string, status int) {
 	g := &git{
 		Path: repository,
+		ctx:  req.Context(),
 	}
 
-	url := "http://" + req.Host + strings.TrimRight(req.URL.Path, "/")
+	url := requestScheme(req) + "://" + req.Host +
+		strings.TrimRight(req.URL.Path, "/")
+
+	// Views of the contents of the repository may be qualified with
+	// a ref, as in /tree/<ref>/<path>. If so, separate it from the
+	// file.
+	refNames := g.RefNames()
+	var pathRef string
+	switch view {
+	case "tree", "blob", "raw", "blame", "log":
+		var ok bool
+		var rest string
+		if pathRef, rest, ok = splitRef(g, refNames, file); ok {
+			file = rest
+			if view == "tree" && len(file) == 0 {
+				file = "./"
+			}
+		}
+	}
 
 	// ref is the git commit reference. If the form is not submitted,
-	// (or is invalid), it is set to "HEAD".
+	// it is taken from the path, or otherwise set to "HEAD". A ref
+	// which is given, but does not exist, is an error.
 	ref := req.FormValue("r")
-	if len(ref) == 0 || !g.RefExists(ref) {
-		ref = "HEAD" // The commit or branch reference
+	if len(ref) != 0 && !g.RefExists(ref) {
+		return page, http.StatusBadRequest
+	}
+	if len(ref) == 0 {
+		ref = pathRef
+		if len(ref) == 0 {
+			ref = "HEAD" // The commit or branch reference
+		}
+	}
+
+	// If a single commit is being viewed, it takes the place of the
+	// ref, and must exist.
+	if view == "commit" {
+		if !g.RefExists(file) {
+			return page, http.StatusNotFound
+		}
+		ref = file
+	}
+
+	// If two refs are being compared, they are given as
+	// <base>...<head>, and both must exist. The head takes the place
+	// of the ref.
+	var base string
+	if view == "compare" {
+		refs := strings.SplitN(file, "...", 2)
+		if len(refs) != 2 || !g.RefExists(refs[0]) || !g.RefExists(refs[1]) {
+			return page, http.StatusNotFound
+		}
+		base, ref = refs[0], refs[1]
 	}
 
 	// maxCommits is the maximum number of commits to be loaded via
@@ -114,13 +243,16 @@ func MakePage(req *http.Request, repository string, file string, isFile bool) (p
 
 	// jsoni is a boolean indicator of whether or not to use the json
 	// interface.
-	jsoni := strings.ToLower(req.FormValue("j")) == "true"
+	jsoni := isJSONRequest(req)
 
 	// If the request is specified as using the JSON interface, then
 	// we switch to that. This usually isn't done, but it is better to
 	// do it here than to wait until the dirinfos are retrieved.
 	git, gitDir := isGit(repository)
 	if jsoni && git {
+		if view == "blob" {
+			return g.ShowFileJSON(ref, file)
+		}
 		return g.ShowJSON(ref, maxCommits)
 	}
 
@@ -130,6 +262,9 @@ func MakePage(req *http.Request, repository string, file string, isFile bool) (p
 	if !git {
 		// Open the file so that it can be read.
 		f, err := os.Open(repository)
+		if os.IsNotExist(err) {
+			return page, http.StatusNotFound
+		}
 		if err != nil || f == nil {
 			// If there is an error opening the file, return 500.
 			return page, http.StatusInternalServerError
@@ -140,21 +275,24 @@ func MakePage(req *http.Request, repository string, file string, isFile bool) (p
 			// If the directory could not be opened, return 500.
 			return page, http.StatusInternalServerError
 		}
-		dirinfos = MakeDirInfos(repository, dirnames)
+		dirinfos = MakeDirInfos(repository, requestUser(req), dirnames)
 	}
 
 	// Get the user.name from the git config
 	owner := gitVarUser()
 
+	// Only the main page of a repository lists its recent commits,
+	// and raw files show neither the number of commits nor of tags.
 	var commits []*Commit
-	if len(file) != 0 {
-		commits = g.CommitsByFile(ref, file, maxCommits)
-	} else {
-		commits = g.Commits(ref, maxCommits)
+	var logErr error
+	if git && len(view) == 0 {
+		commits, logErr = g.Commits(ref, maxCommits)
+	}
+	var commitNum, tagNum int
+	if git && view != "raw" {
+		commitNum = g.TotalCommits()
+		tagNum = len(g.Tags())
 	}
-
-	commitNum := g.TotalCommits()
-	tagNum := len(g.Tags())
 	branch := g.Branch("HEAD")
 	sha := g.SHA(ref)
 
@@ -162,69 +300,129 @@ func MakePage(req *http.Request, repository string, file string, isFile bool) (p
 	t := template.New("Grove!")
 
 	// Set up the gitPage template.
-	pathto := strings.SplitAfter(string(repository), handler.Dir)
 	pageinfo := &gitPage{
 		Owner:     owner,
 		BasePath:  path.Base(repository),
 		URL:       url,
 		GitDir:    gitDir,
 		Host:      req.Host,
+		Scheme:    requestScheme(req),
 		Version:   Version,
-		Path:      pathto[1],
+		Path:      servedPath(repository),
 		Branch:    branch,
 		TagNum:    strconv.Itoa(tagNum),
 		CommitNum: strconv.Itoa(commitNum),
 		SHA:       sha,
 		Location:  template.URL(""),
+		Ref:       ref,
+		RefNames:  refNames,
 	}
-		
-	// TODO: all of the below case blocks may misbehave if the URL
-	// contains a keyword.
+
 	switch {
 	case !git:
 		// This will catch all non-git cases, eliminating the need for
 		// them below.
 		return MakeDirPage(t, doc, pageinfo, req, file, url, dirinfos),
 			http.StatusOK
-	case strings.Contains(req.URL.Path, "tree"):
+	case view == "tree":
 		// This will catch cases needing to serve directories within
 		// git repositories.
-		return MakeTreePage(t, doc, pageinfo, req, file, url,
-			g, ref, pathto), http.StatusOK
-	case strings.Contains(req.URL.Path, "blob"):
+		page, err = MakeTreePage(t, doc, pageinfo, req, file, url,
+			g, ref)
+	case view == "blob":
 		// This will catch cases needing to serve files.
-		return MakeFilePage(t, doc, pageinfo, g, ref, file),
-			http.StatusOK
-	case strings.Contains(req.URL.Path, "raw"):
+		page, err = MakeFilePage(t, doc, pageinfo, g, ref, file)
+	case view == "blame":
+		// This will catch cases needing to show the origin of each
+		// line of a file.
+		page, err = MakeBlamePage(t, doc, pageinfo, g, ref, file)
+	case view == "log":
+		// This will catch cases needing to page through history.
+		page, err = MakeLogPage(t, doc, pageinfo, req, g, ref, file, owner)
+	case view == "search":
+		// This will catch cases needing to search the contents of
+		// the repository.
+		page, err = MakeSearchPage(t, doc, pageinfo, g, ref, req.FormValue("q"))
+	case view == "compare":
+		// This will catch cases needing to compare two refs.
+		page, err = MakeComparePage(t, doc, pageinfo, g, base, ref, owner)
+	case view == "branches":
+		// This will catch cases needing to list branches.
+		page, err = MakeRefsPage(t, doc, pageinfo, g, "branches", "refs/heads")
+	case view == "tags":
+		// This will catch cases needing to list tags.
+		page, err = MakeRefsPage(t, doc, pageinfo, g, "tags", "refs/tags")
+	case view == "raw":
 		// This will catch cases needing to serve files directly.
-		return MakeRawPage(file, ref, g),
-			http.StatusOK
-	case git:
-		// This will catch cases serving the main page of a repository
-		// directory. This needs to be last because the above cases
-		// for "tree" and "blob" will also have `git` as true.
-		return MakeGitPage(t, doc, pageinfo, ref, g, commits,
-				owner, maxCommits, file),
-			http.StatusOK
+		page, err = MakeRawPage(file, ref, g)
+	case view == "commit":
+		// This will catch cases needing to show a single commit.
+		page, err = MakeCommitPage(t, doc, pageinfo, g, ref, owner)
+	case logErr != nil:
+		err = logErr
 	default:
-		// Finally, if this case is reached, something is very wrong.
-		return "", http.StatusInternalServerError
+		// This will catch cases serving the main page of a repository
+		// directory.
+		page, err = MakeGitPage(t, doc, pageinfo, ref, g, commits,
+			owner, maxCommits, file)
 	}
-	return
+	if err != nil {
+		// Errors from git are logged in full, but only their status
+		// is given to the client.
+		l.Printf("View of %q produced error: %s\n", req.URL.Path, err)
+		return "", gitStatus(err)
+	}
+	return page, http.StatusOK
 }
 
-func MakeRawPage(file string, ref string, g *git) string {
-	return string(g.GetFile(ref, file))
+// splitRef separates a ref from the beginning of a path, as in
+// <ref>/<path>. Because refs may contain slashes, the longest leading
+// portion of the path which names a branch or tag is used. Failing
+// that, the first element may be "HEAD" or a commit SHA. If no ref is
+// found, ok is false.
+func splitRef(g *git, refNames []string, p string) (ref, file string, ok bool) {
+	parts := strings.Split(p, "/")
+	for i := len(parts); i > 0; i-- {
+		candidate := strings.Join(parts[:i], "/")
+		for _, n := range refNames {
+			if n == candidate {
+				return candidate, strings.Join(parts[i:], "/"), true
+			}
+		}
+	}
+
+	if parts[0] == "HEAD" || (isSHA(parts[0]) && g.RefExists(parts[0])) {
+		return parts[0], strings.Join(parts[1:], "/"), true
+	}
+	return "", p, false
+}
+
+// isSHA checks whether s could be an abbreviated or full SHA.
+func isSHA(s string) bool {
+	if len(s) < 4 || len(s) > 40 {
+		return false
+	}
+	for _, c := range s {
+		if !strings.ContainsRune("0123456789abcdef", c) {
+			return false
+		}
+	}
+	return true
+}
+
+func MakeRawPage(file string, ref string, g *git) (page string, err error) {
+	contents, err := g.GetFile(ref, file)
+	return string(contents), err
 }
 
 // MakeDirPage makes filesystem directory listings, which are not
 // contained within git projects. It returns an entire webpage as a
 // string.
-func MakeDirPage(t *template.Template, doc bytes.Buffer, pageinfo *gitPage, 
-req *http.Request, file string, url string, dirinfos []os.FileInfo) string {
+func MakeDirPage(t *template.Template, doc bytes.Buffer, pageinfo *gitPage,
+	req *http.Request, file string, url string, dirinfos []os.FileInfo) string {
 	pageinfo.Location = template.URL("/" + file)
 	List := make([]*dirList, 0)
-	if url != ("http://" + req.Host + "/") {
+	if url != (pageinfo.Scheme + "://" + req.Host + "/") {
 		List = append(List, &dirList{
 			URL:   template.URL(url + "/../"),
 			Name:  "..",
@@ -232,10 +430,10 @@ req *http.Request, file string, url string, dirinfos []os.FileInfo) string {
 		})
 	}
 
-	// If is directory, and does not start with '.', and is globally
-	// readable
+	// The dirinfos have already been checked against the policy, so
+	// only directories are left out.
 	for _, info := range dirinfos {
-		if info.IsDir() && CheckPerms(info) {
+		if info.IsDir() {
 			List = append(List, &dirList{
 				URL:   template.URL(info.Name() + "/"),
 				Name:  info.Name(),
@@ -244,17 +442,23 @@ req *http.Request, file string, url string, dirinfos []os.FileInfo) string {
 		}
 	}
 	pageinfo.List = List
-	t, _ = template.ParseFiles(path.Join(*fRes, "templates/dir.html"))
+	t, _ = template.ParseFiles(templatePath("dir.html"))
 
 	return Execute(t, doc, pageinfo)
 }
 
 // MakeFilePage shows the contents of a file within a git project. It
 // returns an entire webpage as a string.
-func MakeFilePage(t *template.Template, doc bytes.Buffer, pageinfo *gitPage, 
-g *git, ref string, file string) (page string) {
+func MakeFilePage(t *template.Template, doc bytes.Buffer, pageinfo *gitPage,
+	g *git, ref string, file string) (page string, err error) {
+	pageinfo.Location = template.URL("/" + file)
+	pageinfo.Parent = parentURL(pageinfo, file)
 	// First we need to get the content,
-	pageinfo.Content = template.HTML(string(g.GetFile(ref, file)))
+	contents, err := g.GetFile(ref, file)
+	if err != nil {
+		return "", err
+	}
+	pageinfo.Content = template.HTML(string(contents))
 	// then we need to figure out how many lines there are.
 	lines := strings.Count(string(pageinfo.Content), "\n")
 	// For each of the lines, we want to prepend
@@ -264,7 +468,6 @@ g *git, ref string, file string) (page string) {
 	// Also, we want to add line numbers.
 	temp := ""
 	temp_html := ""
-	temp_content := strings.SplitAfter(string(pageinfo.Content), "\n")
 
 	// Image support
 	if extention := path.Ext(file); extention == ".png" ||
@@ -276,8 +479,15 @@ g *git, ref string, file string) (page string) {
 		img := base64.StdEncoding.EncodeToString(image)
 		temp_html = "<img src=\"data:image/" + strings.TrimLeft(extention, ".") + ";base64," + img + "\"/>"
 	} else {
+		// Each line is highlighted according to the language of the
+		// file, if it is known.
+		highlighted := highlight(detectLanguage(file, []byte(pageinfo.Content)),
+			string(pageinfo.Content))
 		for j := 1; j <= lines+1; j++ {
-			temp_html += "<div id=\"L-" + strconv.Itoa(j) + "\">" + html.EscapeString(temp_content[j-1]) + "</div>"
+			if j <= lines {
+				highlighted[j-1] += "\n"
+			}
+			temp_html += "<div id=\"L-" + strconv.Itoa(j) + "\">" + highlighted[j-1] + "</div>"
 			temp += "<a href=\"#L-" + strconv.Itoa(j) + "\" class=\"line\">" + strconv.Itoa(j) + "</a><br/>"
 		}
 	}
@@ -286,15 +496,49 @@ g *git, ref string, file string) (page string) {
 	pageinfo.Content = template.HTML(temp_html)
 
 	// Finally, parse it.
-	t, _ = template.ParseFiles(path.Join(*fRes, "templates/file.html"))
-	return Execute(t, doc, pageinfo)
+	t, _ = template.ParseFiles(templatePath("file.html"))
+	return Execute(t, doc, pageinfo), nil
+}
+
+// MakeBlamePage shows the contents of a file within a git project,
+// with each line annotated by the commit which last modified it. It
+// returns an entire webpage as a string.
+func MakeBlamePage(t *template.Template, doc bytes.Buffer, pageinfo *gitPage,
+	g *git, ref string, file string) (page string, err error) {
+	pageinfo.Location = template.URL("/" + file)
+	pageinfo.Parent = parentURL(pageinfo, file)
+
+	lines, err := g.Blame(ref, file)
+	if err != nil {
+		return "", err
+	}
+	pageinfo.Blame = make([]*blameLine, 0, len(lines))
+	for i, b := range lines {
+		short := b.SHA
+		if len(short) > 8 {
+			short = short[:8]
+		}
+		pageinfo.Blame = append(pageinfo.Blame, &blameLine{
+			Number:  strconv.Itoa(i + 1),
+			SHA:     b.SHA,
+			Short:   short,
+			Author:  b.Author,
+			Time:    b.Time,
+			Summary: b.Summary,
+			First:   i == 0 || lines[i-1].SHA != b.SHA,
+			Text:    b.Text,
+		})
+	}
+
+	t, _ = template.ParseFiles(templatePath("blame.html"))
+	return Execute(t, doc, pageinfo), nil
 }
 
 // MakeGitPage shows the "front page" that is the main directory of a
 // git reposiory, including the README and a directory listing. It
 // returns an entire webpage as a string.
-func MakeGitPage(t *template.Template, doc bytes.Buffer, pageinfo *gitPage, ref string, 
-g *git, commits []*Commit, owner string, maxCommits int, file string) (page string) {
+func MakeGitPage(t *template.Template, doc bytes.Buffer, pageinfo *gitPage, ref string,
+	g *git, commits []*Commit, owner string, maxCommits int, file string) (page string, err error) {
 	Logs := make([]*gitLog, 0)
 	for i, c := range commits {
 		if len(c.SHA) == 0 {
@@ -302,19 +546,7 @@ g *git, commits []*Commit, owner string, maxCommits int, file string) (page stri
 			// skip it.
 			continue
 		}
-		var classtype string
-		if c.Author == owner {
-			classtype = "-owner"
-		}
-
-		Logs = append(Logs, &gitLog{
-			Author:    c.Author,
-			Classtype: classtype,
-			SHA:       c.SHA,
-			Time:      c.Time,
-			Subject:   template.HTML(html.EscapeString(c.Subject)),
-			Body:      template.HTML(strings.Replace(html.EscapeString(c.Body), "\n", "<br/>", -1)),
-		})
+		Logs = append(Logs, makeGitLog(c, owner))
 		if i == maxCommits-1 {
 			// but only display certain log messages
 			break
@@ -322,29 +554,220 @@ g *git, commits []*Commit, owner string, maxCommits int, file string) (page stri
 	}
 	pageinfo.Logs = Logs
 	if len(file) == 0 {
-		// Load the README
-		pageinfo.Content = template.HTML(getREADME(g, ref, "README"))
-		pageinfo.Content = template.HTML(getREADME(g, ref, "README.md"))
-		t, _ = template.ParseFiles(path.Join(*fRes, "templates/gitpage.html"))
+		// Load the README, if there is one.
+		for _, name := range []string{"README", "README.md"} {
+			readme, err := getREADME(g, ref, name)
+			if err == nil {
+				pageinfo.Content = template.HTML(readme)
+				break
+			}
+			if !errors.Is(err, ErrNotFound) && !errors.Is(err, ErrBadRef) {
+				return "", err
+			}
+		}
+		t, _ = template.ParseFiles(templatePath("gitpage.html"))
+	}
+	return Execute(t, doc, pageinfo), nil
+}
+
+// MakeCommitPage shows a single commit, including its metadata, its
+// parents, and the diff it introduces to each file. It returns an
+// entire webpage as a string.
+func MakeCommitPage(t *template.Template, doc bytes.Buffer, pageinfo *gitPage,
+	g *git, ref string, owner string) (page string, err error) {
+	commits, err := g.Commits(ref, 1)
+	if err != nil {
+		return "", err
+	}
+	if len(commits) > 0 {
+		pageinfo.Commit = makeGitLog(commits[0], owner)
+	}
+	diff, err := g.Diff(ref)
+	if err != nil {
+		return "", err
+	}
+	pageinfo.Diffs = parseDiff(diff)
+
+	t, _ = template.ParseFiles(templatePath("commit.html"))
+	return Execute(t, doc, pageinfo), nil
+}
+
+// MakeLogPage shows a page of the history of the repository, or of a
+// single file or directory within it. Pages are selected by cursor,
+// using the "after" or "before" form values to give the SHA of the
+// commit which ends the previous page, or begins the next one. The
+// history may be filtered by the "author", "since", and "until" form
+// values. It returns an entire webpage as a string.
+func MakeLogPage(t *template.Template, doc bytes.Buffer, pageinfo *gitPage,
+	req *http.Request, g *git, ref, file string, owner string) (page string, err error) {
+	pageinfo.Location = template.URL("/" + file)
+
+	perPage, err := strconv.Atoi(req.FormValue("c"))
+	if err != nil || perPage <= 0 {
+		perPage = 30
+	}
+
+	h := &historyPage{
+		Author: req.FormValue("author"),
+		Since:  req.FormValue("since"),
+		Until:  req.FormValue("until"),
+	}
+	after, before := req.FormValue("after"), req.FormValue("before")
+	commits, newer, older, err := g.HistoryPage(ref, file,
+		h.Author, h.Since, h.Until, after, before, perPage)
+	if err != nil {
+		return "", err
+	}
+
+	// The whole history is only counted when it is not filtered.
+	if len(file)+len(h.Author)+len(h.Since)+len(h.Until) == 0 {
+		if h.Total, err = g.CountCommits(ref); err != nil {
+			return "", err
+		}
+	}
+
+	// Links to the adjacent pages keep the filters, but replace the
+	// cursor.
+	query := url.Values{}
+	for _, key := range []string{"author", "since", "until", "c"} {
+		if v := req.FormValue(key); len(v) != 0 {
+			query.Set(key, v)
+		}
+	}
+	if newer && len(commits) != 0 {
+		query.Set("before", commits[0].SHA)
+		h.Prev = template.URL("?" + query.Encode())
+		query.Del("before")
+	}
+	if older && len(commits) != 0 {
+		query.Set("after", commits[len(commits)-1].SHA)
+		h.Next = template.URL("?" + query.Encode())
+	}
+	pageinfo.History = h
+
+	Logs := make([]*gitLog, 0, len(commits))
+	for _, c := range commits {
+		if len(c.SHA) == 0 {
+			continue
+		}
+		Logs = append(Logs, makeGitLog(c, owner))
+	}
+	pageinfo.Logs = Logs
+
+	t, _ = template.ParseFiles(templatePath("log.html"))
+	return Execute(t, doc, pageinfo), nil
+}
+
+// MakeSearchPage shows every line in the tree at the given ref which
+// contains the query, with some context. It returns an entire webpage
+// as a string.
+func MakeSearchPage(t *template.Template, doc bytes.Buffer, pageinfo *gitPage,
+	g *git, ref, query string) (page string, err error) {
+	pageinfo.Query = query
+	if len(query) != 0 {
+		pageinfo.Results, pageinfo.Truncated, err = g.Grep(ref, query, 2,
+			grepMaxMatches)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	t, _ = template.ParseFiles(templatePath("search.html"))
+	return Execute(t, doc, pageinfo), nil
+}
+
+// MakeComparePage shows the commits which are reachable from head but
+// not from base, and the combined diff which they introduce. It
+// returns an entire webpage as a string.
+func MakeComparePage(t *template.Template, doc bytes.Buffer, pageinfo *gitPage,
+	g *git, base, head string, owner string) (page string, err error) {
+	pageinfo.Base = base
+	pageinfo.Head = head
+
+	commits, err := g.Commits(base+".."+head, 0)
+	if err != nil {
+		return "", err
+	}
+	Logs := make([]*gitLog, 0)
+	for _, c := range commits {
+		if len(c.SHA) == 0 {
+			continue
+		}
+		Logs = append(Logs, makeGitLog(c, owner))
+	}
+	pageinfo.Logs = Logs
+
+	diff, err := g.DiffRange(base, head)
+	if err != nil {
+		return "", err
+	}
+	pageinfo.Diffs = parseDiff(diff)
+	pageinfo.Added, pageinfo.Deleted = diffStat(pageinfo.Diffs)
+
+	t, _ = template.ParseFiles(templatePath("compare.html"))
+	return Execute(t, doc, pageinfo), nil
+}
+
+// MakeRefsPage lists the refs of a particular kind, such as branches
+// or tags, with the commit at the tip of each. It returns an entire
+// webpage as a string.
+func MakeRefsPage(t *template.Template, doc bytes.Buffer, pageinfo *gitPage,
+	g *git, kind string, prefix string) (page string, err error) {
+	pageinfo.RefKind = kind
+	pageinfo.Refs, err = g.Refs(prefix)
+	if err != nil {
+		return "", err
+	}
+
+	t, _ = template.ParseFiles(templatePath("refs.html"))
+	return Execute(t, doc, pageinfo), nil
+}
+
+// makeGitLog prepares a Commit for display in a template, escaping
+// its message and marking it if it was authored by the owner.
+func makeGitLog(c *Commit, owner string) *gitLog {
+	var classtype string
+	if c.Author == owner {
+		classtype = "-owner"
+	}
+
+	return &gitLog{
+		Author:         c.Author,
+		Email:          c.AuthorEmail,
+		Date:           c.AuthorDate.Format(gitDateFmt),
+		Committer:      c.Committer,
+		CommitterEmail: c.CommitterEmail,
+		CommitDate:     c.CommitDate.Format(gitDateFmt),
+		Classtype:      classtype,
+		SHA:            c.SHA,
+		Tree:           c.Tree,
+		Parents:        c.Parents,
+		Time:           c.Time,
+		Subject:        template.HTML(html.EscapeString(c.Subject)),
+		Body:           template.HTML(strings.Replace(html.EscapeString(c.Body), "\n", "<br/>", -1)),
 	}
-	return Execute(t, doc, pageinfo)
 }
 
 // MakeTreePage makes directory listings from within git repositories.
 // It returns an entire webpage as a string.
-func MakeTreePage(t *template.Template, doc bytes.Buffer, pageinfo *gitPage, req *http.Request, 
-file string, url string, g *git, ref string, pathto []string) (page string) {
+func MakeTreePage(t *template.Template, doc bytes.Buffer, pageinfo *gitPage, req *http.Request,
+	file string, url string, g *git, ref string) (page string, err error) {
+	file = strings.TrimPrefix(file, "./")
 	pageinfo.Location = template.URL("/" + file)
-	if strings.HasSuffix(file, "/") {
+	pageinfo.Parent = parentURL(pageinfo, file)
+	if len(file) == 0 || strings.HasSuffix(file, "/") {
 		List := make([]*dirList, 0)
-		files := g.GetDir(ref, file)
+		files, err := g.GetDir(ref, "./"+file)
+		if err != nil {
+			return "", err
+		}
 		for _, f := range files {
 			if strings.HasSuffix(f, "/") {
 				List = append(List, &dirList{
 					URL:      template.URL(f),
 					Type:     "tree",
 					Host:     req.Host,
-					Path:     pathto[1],
+					Path:     pageinfo.Path,
 					Name:     f,
 					Location: file,
 					Version:  Version,
@@ -356,7 +779,7 @@ file string, url string, g *git, ref string, pathto []string) (page string) {
 					Type:     "blob",
 					Name:     f,
 					Host:     req.Host,
-					Path:     pathto[1],
+					Path:     pageinfo.Path,
 					Location: file,
 					Class:    "file",
 					Version:  Version,
@@ -364,11 +787,163 @@ file string, url string, g *git, ref string, pathto []string) (page string) {
 			}
 		}
 		pageinfo.List = List
-		t, _ = template.ParseFiles(path.Join(*fRes, "templates/tree.html"))
+		t, _ = template.ParseFiles(templatePath("tree.html"))
+	}
+	return Execute(t, doc, pageinfo), nil
+}
+
+// parentURL returns the URL of the tree containing the given file or
+// directory, at the ref being viewed. If the file is at the top level
+// of the repository, it is the repository's main page.
+func parentURL(pageinfo *gitPage, file string) template.URL {
+	base := pageinfo.Scheme + "://" + pageinfo.Host + pageinfo.Path
+	file = strings.TrimRight(file, "/")
+	if len(file) == 0 {
+		return template.URL(base + "/")
+	}
+	dir := path.Dir(file)
+	if dir == "." {
+		dir = ""
+	} else {
+		dir += "/"
+	}
+	return template.URL(base + "/tree/" + pageinfo.Ref + "/" + dir)
+}
+
+// MakeRootSearchPage searches every servable repository below the top
+// level directory for the query given in the "q" form value. Names of
+// repositories and their READMEs are always searched, and if the
+// "code" form value is "true", so is the code at HEAD. It returns an
+// entire webpage as a string.
+func MakeRootSearchPage(req *http.Request) (page string, status int) {
+	query := req.FormValue("q")
+	pageinfo := &gitPage{
+		Owner:   gitVarUser(),
+		Host:    req.Host,
+		Scheme:  requestScheme(req),
+		Version: Version,
+		Query:   query,
+		Code:    strings.ToLower(req.FormValue("code")) == "true",
+	}
+
+	if len(query) != 0 {
+		lower := strings.ToLower(query)
+		var repositories []string
+		for _, r := range roots {
+			for _, repository := range FindRepositories(r.Dir) {
+				if CanRead(requestUser(req), repository) {
+					repositories = append(repositories, repository)
+				}
+			}
+		}
+		grepped, matches := 0, 0
+		for _, repository := range repositories {
+			name := servedPath(repository)
+			if len(name) == 0 {
+				name = "/"
+			}
+			g := &git{Path: repository, ctx: req.Context()}
+			m := &repoMatch{
+				Name:      name,
+				URL:       template.URL(pageinfo.Scheme + "://" + req.Host + name),
+				NameMatch: strings.Contains(strings.ToLower(name), lower),
+			}
+			// Repositories which cannot be read are simply left out
+			// of the results.
+			for _, readme := range []string{"README", "README.md"} {
+				contents, _ := g.GetFile("HEAD", readme)
+				for _, line := range strings.Split(string(contents), "\n") {
+					if strings.Contains(strings.ToLower(line), lower) {
+						m.Readme = append(m.Readme, line)
+					}
+				}
+			}
+			if pageinfo.Code {
+				if grepped >= searchMaxRepositories || matches >= grepMaxMatches {
+					pageinfo.Truncated = true
+				} else {
+					var truncated bool
+					m.Code, truncated, _ = g.Grep("HEAD", query, 0,
+						grepMaxMatches-matches)
+					for _, f := range m.Code {
+						matches += len(f.Lines)
+					}
+					pageinfo.Truncated = pageinfo.Truncated || truncated
+					grepped++
+				}
+			}
+			if m.NameMatch || len(m.Readme) != 0 || len(m.Code) != 0 {
+				pageinfo.Matches = append(pageinfo.Matches, m)
+			}
+		}
 	}
+
+	t, err := template.ParseFiles(templatePath("search-all.html"))
+	if err != nil {
+		l.Println(err)
+		return "", http.StatusInternalServerError
+	}
+	var doc bytes.Buffer
+	return Execute(t, doc, pageinfo), http.StatusOK
+}
+
+// MakeRootsPage lists the directories being served, when there are
+// several. It returns an entire webpage as a string.
+func MakeRootsPage(req *http.Request) (page string, status int) {
+	pageinfo := &gitPage{
+		Owner:    gitVarUser(),
+		Host:     req.Host,
+		Scheme:   requestScheme(req),
+		Version:  Version,
+		Location: template.URL("/"),
+	}
+	for _, r := range roots {
+		pageinfo.List = append(pageinfo.List, &dirList{
+			URL:   template.URL(r.Name + "/"),
+			Name:  r.Name,
+			Class: "dir",
+		})
+	}
+	t, err := template.ParseFiles(templatePath("dir.html"))
+	if err != nil {
+		l.Println(err)
+		return "", http.StatusInternalServerError
+	}
+	var doc bytes.Buffer
+	return Execute(t, doc, pageinfo), http.StatusOK
+}
+
+// MakeErrorPage describes a request which could not be served, with
+// the given HTTP status. It returns an entire webpage as a string.
+func MakeErrorPage(req *http.Request, status int) (page string) {
+	pageinfo := &gitPage{
+		Owner:    gitVarUser(),
+		Host:     req.Host,
+		Scheme:   requestScheme(req),
+		Version:  Version,
+		Location: template.URL(req.URL.Path),
+		Status:   status,
+		Message:  http.StatusText(status),
+	}
+	t, err := template.ParseFiles(templatePath("error.html"))
+	if err != nil {
+		l.Println(err)
+		return "Could not serve " + req.URL.Path + "\n" + http.StatusText(status)
+	}
+	var doc bytes.Buffer
 	return Execute(t, doc, pageinfo)
 }
 
+// templatePath retrieves the path of the named template, which is in
+// the templates directory if one was given, or otherwise in that of
+// the resources directory.
+func templatePath(name string) string {
+	if templates := setting(fTemplates); len(templates) != 0 {
+		return path.Join(templates, name)
+	}
+	return path.Join(setting(fRes), "templates", name)
+}
+
 // Execute executes a template (using html/template) and returns the
 // result as a string.
 func Execute(t *template.Template, doc bytes.Buffer, pageinfo *gitPage) string {